@@ -3,9 +3,22 @@
 package gooptional
 
 import (
+	"bytes"
+	"database/sql"
 	"database/sql/driver"
+	"encoding/gob"
 	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"os"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bantling/gofuncs"
 	"github.com/bantling/goiter"
@@ -18,30 +31,287 @@ const (
 	// ZeroValueIsPresent is the default, and indicates a zero value is considered present
 	ZeroValueIsPresent ZeroValueIsPresentFlags = false
 	// ZeroValueIsEmpty indicates a zero value is considered empty
-	ZeroValueIsEmpty
+	ZeroValueIsEmpty ZeroValueIsPresentFlags = true
 )
 
 // Optional is a mostly immutable generic wrapper for any kind of value with a present flag.
-// The only mutable operation is the implementation of the sql.Scanner interface.
+// The only mutable operations are the implementations of decoding interfaces, such as sql.Scanner and gob.GobDecoder.
 // The zero value is ready to use.
 type Optional struct {
-	value   interface{}
-	present bool
+	value    interface{}
+	present  bool
+	redacted bool
 }
 
 var (
-	errNotPresent = "No value present"
-	emptyString   = "Optional"
+	errNotPresent  = "No value present"
+	redactedString = "Optional (****)"
+	emptyString    = "Optional"
 )
 
 // Of returns an Optional.
 // If no value or a nil value is provided, a new empty Optional is returned.
+// If the value is already an Optional, it is returned as is rather than wrapped, since an Optional of an Optional is almost never intended.
+// Similarly, an OptionalDecimal or OptionalUUID is flattened by its AsOptional method into an Optional of its *big.Rat or [16]byte value,
+// or an empty Optional. The flattened Optional loses the typed behaviour: Value returns the *big.Rat or [16]byte for the driver to handle,
+// and String formats it with %v, rather than producing the canonical decimal or uuid string.
+// Keep the typed optional, or convert back with ToOptionalDecimal or ToOptionalUUID, where the typed behaviour is needed.
 // Otherwise a new Optional that wraps the value is returned.
 func Of(value ...interface{}) Optional {
 	v := gofuncs.IndexOf(value, 0)
+
+	switch opt := v.(type) {
+	case Optional:
+		return opt
+	case OptionalDecimal:
+		return opt.AsOptional()
+	case OptionalUUID:
+		return opt.AsOptional()
+	}
+
 	return gofuncs.Ternary(gofuncs.IsNil(v), Optional{}, Optional{value: v, present: true}).(Optional)
 }
 
+// OfAll returns a new slice of Optionals of each of the given values, using the same rules as Of.
+// Each non-nil value is present, including zero values such as empty strings, while each nil value is empty.
+// It is the dual of Partition, adapting a slice of plain values to APIs that accept Optionals.
+func OfAll(values ...interface{}) []Optional {
+	result := make([]Optional, len(values))
+	for i, value := range values {
+		result[i] = Of(value)
+	}
+
+	return result
+}
+
+// FromResult returns an Optional of the result of a function that returns a value and an error.
+// If the error is not nil, a new empty Optional is returned, else the value is wrapped using the same rules as Of.
+// For example, FromResult(strconv.Atoi(s)) is present only if s is a valid int.
+func FromResult(value interface{}, err error) Optional {
+	if err != nil {
+		return Optional{}
+	}
+
+	return Of(value)
+}
+
+// Reconstruct returns an Optional from a value and present flag that were previously read from an Optional by Get.
+// It allows external codecs to rebuild an Optional after reading both fields.
+// If present is false, or the value is nil, a new empty Optional is returned, else a new Optional that wraps the value.
+func Reconstruct(value interface{}, present bool) Optional {
+	return gofuncs.Ternary(present, Of(value), Optional{}).(Optional)
+}
+
+// OfRecover returns an Optional of the result of the given function, using the same rules as Of.
+// If the function panics, the panic is recovered and a new empty Optional is returned.
+// This is useful for wrapping lookups that may panic, such as a type assertion.
+func OfRecover(f func() interface{}) (opt Optional) {
+	defer func() {
+		if recover() != nil {
+			opt = Optional{}
+		}
+	}()
+
+	return Of(f())
+}
+
+// LookupEnvOptional returns a present Optional of the named environment variable's string value if it is set, even if it is set to an empty string.
+// If the variable is not set, a new empty Optional is returned.
+// This distinguishes an unset variable from one that is set to an empty string.
+func LookupEnvOptional(key string) Optional {
+	if value, set := os.LookupEnv(key); set {
+		return Optional{value: value, present: true}
+	}
+
+	return Optional{}
+}
+
+// lookupEnvParsed returns an empty Optional if the named environment variable is not set, else an Optional of the parsed value.
+// An error is returned if the value cannot be parsed, including if it is set to an empty string,
+// which wraps the parse error so that errors.Is can distinguish strconv.ErrSyntax from strconv.ErrRange.
+func lookupEnvParsed(key string, kind string, parse func(string) (interface{}, error)) (Optional, error) {
+	str, set := os.LookupEnv(key)
+	if !set {
+		return Optional{}, nil
+	}
+
+	value, err := parse(str)
+	if err != nil {
+		return Optional{}, fmt.Errorf("environment variable %s value %q is not a valid %s: %w", key, str, kind, err)
+	}
+
+	return Optional{value: value, present: true}, nil
+}
+
+// LookupEnvInt returns a new empty Optional if the named environment variable is not set, else an Optional of its value parsed as an int.
+// An error is returned if the value is not a valid int, including if it is set to an empty string, which wraps the strconv error.
+func LookupEnvInt(key string) (Optional, error) {
+	return lookupEnvParsed(key, "int", func(str string) (interface{}, error) { return strconv.Atoi(str) })
+}
+
+// LookupEnvFloat returns a new empty Optional if the named environment variable is not set, else an Optional of its value parsed as a float64.
+// An error is returned if the value is not a valid float, including if it is set to an empty string, which wraps the strconv error.
+func LookupEnvFloat(key string) (Optional, error) {
+	return lookupEnvParsed(key, "float", func(str string) (interface{}, error) { return strconv.ParseFloat(str, 64) })
+}
+
+// LookupEnvBool returns a new empty Optional if the named environment variable is not set, else an Optional of its value parsed as a bool.
+// Any value accepted by strconv.ParseBool is valid, such as true, false, 1, and 0.
+// An error is returned if the value is not a valid bool, including if it is set to an empty string, which wraps the strconv error.
+func LookupEnvBool(key string) (Optional, error) {
+	return lookupEnvParsed(key, "bool", func(str string) (interface{}, error) { return strconv.ParseBool(str) })
+}
+
+// OfRegexpMatch returns a present Optional of the string matched by the regexp in s, or a new empty Optional if it does not match.
+// If the regexp has any capture groups, the value is the string matched by the first group instead of the whole match.
+// If the first group does not participate in the match, such as (a)?b matching "b", a new empty Optional is returned.
+func OfRegexpMatch(re *regexp.Regexp, s string) Optional {
+	return ofRegexpGroup(re, s, gofuncs.Ternary(re.NumSubexp() > 0, 1, 0).(int))
+}
+
+// OfRegexpNamedMatch returns a present Optional of the string matched by the named capture group of the regexp in s.
+// A new empty Optional is returned if the regexp does not match, the named group does not participate in the match,
+// or the regexp has no group of the given name.
+func OfRegexpNamedMatch(re *regexp.Regexp, s string, name string) Optional {
+	group := -1
+	if name != "" {
+		for i, groupName := range re.SubexpNames() {
+			if groupName == name {
+				group = i
+				break
+			}
+		}
+	}
+
+	return ofRegexpGroup(re, s, group)
+}
+
+// ofRegexpGroup returns a present Optional of the string matched by the group at the given index, where 0 is the whole match.
+// A negative index results in a new empty Optional.
+func ofRegexpGroup(re *regexp.Regexp, s string, group int) Optional {
+	if group < 0 {
+		return Optional{}
+	}
+
+	if loc := re.FindStringSubmatchIndex(s); (loc != nil) && (loc[2*group] >= 0) {
+		return Optional{value: s[loc[2*group]:loc[2*group+1]], present: true}
+	}
+
+	return Optional{}
+}
+
+// FirstPresentByKeys returns the first present Optional in the map, checking the keys in the order given.
+// Keys that are not in the map are skipped.
+// If no key refers to a present Optional, a new empty Optional is returned.
+func FirstPresentByKeys(m map[string]Optional, keys ...string) Optional {
+	for _, key := range keys {
+		if opt := m[key]; opt.present {
+			return opt
+		}
+	}
+
+	return Optional{}
+}
+
+// FromMap returns an Optional of the value of the given key in the given map if the key exists, else a new empty Optional.
+// This distinguishes a key that exists with a zero value, such as an empty string, from a key that does not exist.
+// As with Of, a nil value results in an empty Optional.
+// The map may be of any type, and the key must be assignable to the map key type, else a panic occurs.
+func FromMap(m interface{}, key interface{}) Optional {
+	mv := reflect.ValueOf(m)
+	if mv.Kind() != reflect.Map {
+		panic(fmt.Sprintf("FromMap requires a map, not %T", m))
+	}
+
+	kv := reflect.ValueOf(key)
+	if !kv.IsValid() && (mv.Type().Key().Kind() == reflect.Interface) {
+		// A nil key of a map with interface keys
+		kv = reflect.Zero(mv.Type().Key())
+	}
+
+	if !kv.IsValid() || !kv.Type().AssignableTo(mv.Type().Key()) {
+		panic(fmt.Sprintf("FromMap requires a key of type %s, not %T", mv.Type().Key(), key))
+	}
+
+	if v := mv.MapIndex(kv); v.IsValid() {
+		return Of(v.Interface())
+	}
+
+	return Optional{}
+}
+
+// FirstPresent calls the given suppliers concurrently, and returns the first present Optional that any of them return.
+// If all of them return an empty Optional, or none returns a present Optional before the timeout, a new empty Optional is returned.
+// Suppliers that have not finished when FirstPresent returns are not stopped, but their results are discarded without blocking them.
+func FirstPresent(timeout time.Duration, suppliers ...func() Optional) Optional {
+	// Buffer all results, so that suppliers that finish after a winner or timeout do not block forever
+	results := make(chan Optional, len(suppliers))
+	for _, supplier := range suppliers {
+		go func(supplier func() Optional) {
+			results <- supplier()
+		}(supplier)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for range suppliers {
+		select {
+		case opt := <-results:
+			if opt.present {
+				return opt
+			}
+		case <-timer.C:
+			return Optional{}
+		}
+	}
+
+	return Optional{}
+}
+
+// RequireAll returns nil if all of the given optionals are present, else an error listing the indexes of the empty ones.
+// Any type with an IsPresent method can be passed, such as Optional and OptionalDecimal.
+func RequireAll(opts ...interface{ IsPresent() bool }) error {
+	var empty []string
+	for i, opt := range opts {
+		if !opt.IsPresent() {
+			empty = append(empty, strconv.Itoa(i))
+		}
+	}
+
+	if empty == nil {
+		return nil
+	}
+
+	return fmt.Errorf("optionals at indexes [%s] are not present", strings.Join(empty, ", "))
+}
+
+// AllPresent returns true if all of the given optionals are present, stopping at the first empty one.
+// Any type with an IsPresent method can be passed, such as Optional and OptionalDecimal.
+// If no optionals are given, the result is true.
+func AllPresent(opts ...interface{ IsPresent() bool }) bool {
+	for _, opt := range opts {
+		if !opt.IsPresent() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AnyPresent returns true if any of the given optionals are present, stopping at the first present one.
+// Any type with an IsPresent method can be passed, such as Optional and OptionalDecimal.
+// If no optionals are given, the result is false.
+func AnyPresent(opts ...interface{ IsPresent() bool }) bool {
+	for _, opt := range opts {
+		if opt.IsPresent() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Get returns the wrapped value and whether or not it is present.
 // The wrapped value is only valid if the boolean is true.
 func (o Optional) Get() (interface{}, bool) {
@@ -53,17 +323,150 @@ func (o Optional) MustGet() interface{} {
 	return gofuncs.PanicVBM(o.value, o.present, errNotPresent)
 }
 
+// GetOr returns the wrapped value and a nil error if it is present, else nil and the given error.
+// This allows the caller to supply an error that explains why the value is missing.
+func (o Optional) GetOr(err error) (interface{}, error) {
+	if o.present {
+		return o.value, nil
+	}
+
+	return nil, err
+}
+
+// MustGetNamed is the same as MustGet, except that the panic message names the given field, eg field "email" not present.
+// This identifies which value was missing in code that unwraps many fields.
+func (o Optional) MustGetNamed(field string) interface{} {
+	if !o.present {
+		panic(fmt.Sprintf("field %q not present", field))
+	}
+
+	return o.value
+}
+
+// MustSatisfy returns the wrapped value if it is present and the predicate returns true for it, else it panics with the given message.
+// predicate must be a func that receives a type the wrapped value can be converted into and returns a bool, as for Filter.
+// This is a fail fast unwrap for invariants whose violation is a programming error.
+func (o Optional) MustSatisfy(predicate interface{}, msg string) interface{} {
+	if !o.present || !gofuncs.Filter(predicate)(o.value) {
+		panic(msg)
+	}
+
+	return o.value
+}
+
 // OrElse returns the wrapped value if it is present, else it returns the given value.
 func (o Optional) OrElse(value interface{}) interface{} {
 	return gofuncs.Ternary(o.present, o.value, value)
 }
 
+// MergeInto returns the wrapped value if it is present, else the given base value.
+// It is the same as OrElse, named to express applying a partial update onto a base value, such as for a PATCH request.
+func (o Optional) MergeInto(base interface{}) interface{} {
+	return o.OrElse(base)
+}
+
+// MergeIntoOpt returns this Optional if it is present, else the given base Optional.
+// This merges a partial update onto a base that may itself be empty.
+func (o Optional) MergeIntoOpt(base Optional) Optional {
+	return gofuncs.Ternary(o.present, o, base).(Optional)
+}
+
+// OrElseFrom returns the wrapped value if it is present, else the result of the conversion function applied to the other Optional's value
+// if it is present, else nil.
+// conv must be a func that receives a type the other value can be converted into and returns a single value, as for Map,
+// and is only called if this Optional is empty and the other is present.
+// This allows falling back to an Optional of a different type, such as an int parsed from a string.
+func (o Optional) OrElseFrom(other Optional, conv interface{}) interface{} {
+	if o.present {
+		return o.value
+	}
+
+	if other.present {
+		return gofuncs.Map(conv)(other.value)
+	}
+
+	return nil
+}
+
+// OrElseOpt returns the wrapped value if it is present, else the other Optional's wrapped value if it is present, else nil.
+// Unlike OrElse, the fallback is another Optional, and the result is a plain value rather than an Optional.
+func (o Optional) OrElseOpt(other Optional) interface{} {
+	if o.present {
+		return o.value
+	}
+
+	if other.present {
+		return other.value
+	}
+
+	return nil
+}
+
 // OrElseGet returns the wrapped value if it is present, else it returns the result of the given function.
 // supplier must be a func of no args that returns a single value to be wrapped.
 func (o Optional) OrElseGet(supplier interface{}) interface{} {
 	return gofuncs.TernaryOf(o.present, o.MustGet, supplier)
 }
 
+// OrElseUpdate returns this Optional if it is present, else it returns Of(the result of the given function).
+// supplier must be a func of no args that returns a single value to be wrapped, and is only called if this Optional is empty.
+// Unlike OrElseGet, the result is an Optional, allowing further chaining.
+func (o Optional) OrElseUpdate(supplier interface{}) Optional {
+	if o.present {
+		return o
+	}
+
+	return Of(o.OrElseGet(supplier))
+}
+
+// OrElseConvert returns the wrapped value if it is present, else the given default value, converted to the given type.
+// This provides a consistent result type when the wrapped value and default are different types, such as int and int64.
+// Conversions follow the rules of reflect.Value.Convert, except that an integer is not converted to a string.
+// An error is returned if the value is nil or cannot be converted, including when the types are convertible but the value is not,
+// such as a slice that is too short to convert to an array pointer.
+func (o Optional) OrElseConvert(value interface{}, typ reflect.Type) (result interface{}, err error) {
+	result = o.OrElse(value)
+	if result == nil {
+		return nil, fmt.Errorf("cannot convert <nil> to %s", typ)
+	}
+
+	rv := reflect.ValueOf(result)
+	if !rv.Type().ConvertibleTo(typ) || ((typ.Kind() == reflect.String) && isIntKind(rv.Kind())) {
+		return nil, fmt.Errorf("cannot convert a value of type %s to %s", rv.Type(), typ)
+	}
+
+	// Convert panics if the value cannot be converted even though the types are convertible
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("cannot convert a value of type %s to %s: %v", rv.Type(), typ, r)
+		}
+	}()
+
+	return rv.Convert(typ).Interface(), nil
+}
+
+// OrElseAs returns the wrapped value converted to the type of the given default value if it is present, else the default value.
+// If the wrapped value cannot be converted as described by OrElseConvert, the default value is returned.
+// A nil default value results in the wrapped value if it is present, else nil.
+func (o Optional) OrElseAs(value interface{}) interface{} {
+	result, err := o.OrElseAsErr(value)
+	return gofuncs.Ternary(err == nil, result, value)
+}
+
+// OrElseAsErr is the same as OrElseAs, except that an error is returned if the wrapped value cannot be converted.
+func (o Optional) OrElseAsErr(value interface{}) (interface{}, error) {
+	if value == nil {
+		return o.OrElse(nil), nil
+	}
+
+	return o.OrElseConvert(value, reflect.TypeOf(value))
+}
+
+// isIntKind returns true if the kind is a signed or unsigned integer kind
+func isIntKind(kind reflect.Kind) bool {
+	return ((kind >= reflect.Int) && (kind <= reflect.Int64)) || ((kind >= reflect.Uint) && (kind <= reflect.Uintptr))
+}
+
 // OrElsePanic returns the wrapped value if it is present, else it panics with the result of the given function
 func (o Optional) OrElsePanic(f func() string) interface{} {
 	return gofuncs.PanicVBM(o.value, o.present, f())
@@ -79,6 +482,73 @@ func (o Optional) IsPresent() bool {
 	return o.present
 }
 
+// IsPresentAnd returns true if this Optional is present and the predicate returns true for the value.
+// The predicate must be a func(any) bool, as for Filter, and is not invoked if this Optional is empty.
+func (o Optional) IsPresentAnd(predicate interface{}) bool {
+	return o.present && gofuncs.Filter(predicate)(o.value)
+}
+
+// IsEmptyOr returns true if this Optional is empty or the predicate returns true for the value.
+// The predicate must be a func(any) bool, as for Filter, and is not invoked if this Optional is empty.
+func (o Optional) IsEmptyOr(predicate interface{}) bool {
+	return !o.present || gofuncs.Filter(predicate)(o.value)
+}
+
+// bigFloatOf returns the given value as a *big.Float and true if it is of an integer or floating point kind.
+// The conversion is exact, so no precision is lost. NaN cannot be converted, and returns false.
+func bigFloatOf(val interface{}) (*big.Float, bool) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); !math.IsNaN(f) {
+			return new(big.Float).SetFloat64(f), true
+		}
+	}
+
+	return nil, false
+}
+
+// EqualNumeric returns true if both Optionals are empty, or both are present with equal values.
+// If both values are of integer or floating point kinds, they are compared numerically regardless of type,
+// so that Of(5) and Of(int64(5)) and Of(5.0) are all equal.
+// The numeric comparison is exact: an int64 beyond 2^53 is only equal to a float64 that has exactly the same value,
+// rather than to the nearest float64. NaN is never equal to anything.
+// Otherwise, the values are compared with reflect.DeepEqual.
+func (o Optional) EqualNumeric(opt Optional) bool {
+	if !(o.present && opt.present) {
+		return o.present == opt.present
+	}
+
+	if a, aok := bigFloatOf(o.value); aok {
+		if b, bok := bigFloatOf(opt.value); bok {
+			return a.Cmp(b) == 0
+		}
+	}
+
+	return reflect.DeepEqual(o.value, opt.value)
+}
+
+// Changed returns true if the two Optionals differ: one is present and the other is empty,
+// or both are present and their values are not equal according to reflect.DeepEqual.
+func Changed(a, b Optional) bool {
+	if a.present != b.present {
+		return true
+	}
+
+	return a.present && !reflect.DeepEqual(a.value, b.value)
+}
+
+// Diff returns b if it has Changed from a, else an empty Optional.
+// This represents the new value only if it changed, such as when generating a partial update.
+// Note that if b is empty, the result is empty whether or not a changed to empty; use Changed to distinguish these cases.
+func Diff(a, b Optional) Optional {
+	return gofuncs.Ternary(Changed(a, b), b, Optional{}).(Optional)
+}
+
 // IfEmpty executes the function only if the value is not present.
 func (o Optional) IfEmpty(f func()) {
 	if !o.present {
@@ -94,6 +564,12 @@ func (o Optional) IfPresent(consumer interface{}) {
 	}
 }
 
+// ForEach is an alias of IfPresent for readers familiar with collection APIs.
+// An Optional is a collection of zero or one elements, so the consumer is executed at most once.
+func (o Optional) ForEach(consumer interface{}) {
+	o.IfPresent(consumer)
+}
+
 // IfPresentOrElse executes the consumer function with the wrapped value if the value is present, otherwise executes the function of no args.
 // consumer must be a func that receives a type the wrapped value can be converted into and has no return values.
 func (o Optional) IfPresentOrElse(consumer interface{}, f func()) {
@@ -104,21 +580,93 @@ func (o Optional) IfPresentOrElse(consumer interface{}, f func()) {
 	}
 }
 
+// Also executes the consumer function with the wrapped value if the value is present, otherwise executes the function of no args,
+// and returns this Optional unchanged.
+// It is the chainable version of IfPresentOrElse.
+func (o Optional) Also(consumer interface{}, f func()) Optional {
+	o.IfPresentOrElse(consumer, f)
+	return o
+}
+
+// PeekEmpty executes the function only if the value is not present, and returns this Optional unchanged.
+// It is the chainable version of IfEmpty.
+func (o Optional) PeekEmpty(f func()) Optional {
+	if !o.present {
+		f()
+	}
+
+	return o
+}
+
 // Iter returns an *Iter of one element containing the wrapped value if present, else an empty Iter.
 // See Iter for typed methods that return builtin types.
 func (o Optional) Iter() *goiter.Iter {
 	return gofuncs.Ternary(o.present, goiter.Of(o.value), goiter.Of()).(*goiter.Iter)
 }
 
+// Chan returns a channel that receives the wrapped value if present and is then closed, else a closed empty channel.
+// The channel is buffered, so the value is sent without blocking and no goroutine is needed.
+// This allows an Optional to participate in a select statement.
+func (o Optional) Chan() <-chan interface{} {
+	ch := make(chan interface{}, 1)
+	if o.present {
+		ch <- o.value
+	}
+
+	close(ch)
+	return ch
+}
+
+// IterInt returns an *Iter of one element containing the wrapped value if it is present and an int, else an empty Iter.
+// A value of any other type, including other integer types, results in an empty Iter rather than a panic,
+// so the Iter IntValue methods are always safe to call.
+func (o Optional) IterInt() *goiter.Iter {
+	_, isInt := o.value.(int)
+	return gofuncs.Ternary(o.present && isInt, goiter.Of(o.value), goiter.Of()).(*goiter.Iter)
+}
+
+// IterFloat returns an *Iter of one element containing the wrapped value if it is present and a float64, else an empty Iter.
+// A value of any other type, including float32, results in an empty Iter rather than a panic,
+// so the Iter Float64Value methods are always safe to call.
+func (o Optional) IterFloat() *goiter.Iter {
+	_, isFloat := o.value.(float64)
+	return gofuncs.Ternary(o.present && isFloat, goiter.Of(o.value), goiter.Of()).(*goiter.Iter)
+}
+
+// IterString returns an *Iter of one element containing the wrapped value if it is present and a string, else an empty Iter.
+// A value of any other type results in an empty Iter rather than a panic,
+// so the Iter StringValue methods are always safe to call.
+func (o Optional) IterString() *goiter.Iter {
+	_, isString := o.value.(string)
+	return gofuncs.Ternary(o.present && isString, goiter.Of(o.value), goiter.Of()).(*goiter.Iter)
+}
+
 // Filter applies the predicate to the value of this Optional.
 // Returns this Optional only if this Optional is present and the filter returns true for the value.
 // Otherwise an empty Optional is returned.
 // The predicate must be a func(any) bool, where the arg is compatible with the value of this Optional.
 // Use gofuncs for predicate conjunctions, disjuctions, negations, etc.
+// Use FilterChain to find out which of a chain of filters dropped the value.
 func (o Optional) Filter(predicate interface{}) Optional {
 	return gofuncs.Ternary(o.present && gofuncs.Filter(predicate)(o.value), o, Optional{}).(Optional)
 }
 
+// Redacted returns a copy of this Optional whose String, GoString, and Format render "Optional (****)" for every verb if it is present,
+// and whose SQLLiteral renders '****', so that secrets such as passwords and tokens are not accidentally written to logs.
+// An empty Optional still renders as "Optional". Get, ValueString, and other getters still return the actual value.
+// Only Filter and FilterChain preserve redaction, any other operation that produces a new value returns an unredacted Optional.
+// Redaction is sticky on the receiver of decoding methods such as Scan, OnlyChangedScan, and GobDecode,
+// which replace the value but leave a Redacted Optional redacted, even if it becomes empty.
+func (o Optional) Redacted() Optional {
+	o.redacted = true
+	return o
+}
+
+// IsRedacted returns true if this Optional was produced by Redacted
+func (o Optional) IsRedacted() bool {
+	return o.redacted
+}
+
 // Map the wrapped value with the given mapping function, which may return a different type.
 // An empty Optional is returned if any of the following is true:
 // - This Optional is not present. In this case, the mapping function is not invoked.
@@ -131,7 +679,22 @@ func (o Optional) Map(f interface{}, zeroValIsPresent ...ZeroValueIsPresentFlags
 		return Optional{}
 	}
 
-	v := gofuncs.Map(f)(o.value)
+	return o.MapFunc(gofuncs.Map(f), zeroValIsPresent...)
+}
+
+// MapFunc is the same as Map, except that the mapping function already accepts and returns interface{},
+// so no reflection is required to call it. This is more efficient than Map for hot paths.
+// The rules for nil and zero results are the same as Map.
+func (o Optional) MapFunc(f func(interface{}) interface{}, zeroValIsPresent ...ZeroValueIsPresentFlags) Optional {
+	if !o.present {
+		return Optional{}
+	}
+
+	return ofMapped(f(o.value), zeroValIsPresent)
+}
+
+// ofMapped returns an Optional of a mapped value, using the nil and zero value rules of Map
+func ofMapped(v interface{}, zeroValIsPresent []ZeroValueIsPresentFlags) Optional {
 	if gofuncs.IsNil(v) {
 		return Optional{}
 	}
@@ -143,6 +706,259 @@ func (o Optional) Map(f interface{}, zeroValIsPresent ...ZeroValueIsPresentFlags
 	return Of(v)
 }
 
+// MapWhile is the same as MapFunc, except that the mapping function also returns whether to continue.
+// If the mapping function returns false, an empty Optional is returned, terminating the chain.
+// Otherwise the result is wrapped using the same nil and zero value rules as Map.
+// Unlike Filter, which tests the value before mapping, the decision is made by the mapping function as part of the mapping.
+func (o Optional) MapWhile(f func(interface{}) (interface{}, bool), zeroValIsPresent ...ZeroValueIsPresentFlags) Optional {
+	if !o.present {
+		return Optional{}
+	}
+
+	if v, cont := f(o.value); cont {
+		return ofMapped(v, zeroValIsPresent)
+	}
+
+	return Optional{}
+}
+
+// MapErr is the same as MapFunc, except that the mapping function may fail with an error.
+// If this Optional is empty, the mapping function is not invoked, and an empty Optional and nil error are returned.
+// If the mapping function returns an error, an empty Optional and the error are returned.
+// Otherwise the result is wrapped using the same nil and zero value rules as Map, with a nil error.
+func (o Optional) MapErr(f func(interface{}) (interface{}, error), zeroValIsPresent ...ZeroValueIsPresentFlags) (Optional, error) {
+	if !o.present {
+		return Optional{}, nil
+	}
+
+	v, err := f(o.value)
+	if err != nil {
+		return Optional{}, err
+	}
+
+	return ofMapped(v, zeroValIsPresent), nil
+}
+
+// MapAs is the same as Map, except that a present result must be assignable to the given type, which may be an interface type.
+// If the result is present but not assignable, an empty Optional and an error are returned.
+// This catches a mapping function that produces the wrong type.
+func (o Optional) MapAs(f interface{}, typ reflect.Type, zeroValIsPresent ...ZeroValueIsPresentFlags) (Optional, error) {
+	result := o.Map(f, zeroValIsPresent...)
+	if result.present {
+		if vt := reflect.TypeOf(result.value); !vt.AssignableTo(typ) {
+			return Optional{}, fmt.Errorf("the mapped value of type %s is not assignable to %s", vt, typ)
+		}
+	}
+
+	return result, nil
+}
+
+// MapAssert is the same as MapFunc, except that if the wrapped value is not assignable to the given type,
+// which may be an interface type, a new empty Optional is returned without calling the mapping function.
+// This guards against a value of an unexpected type, such as after a Scan.
+func (o Optional) MapAssert(typ reflect.Type, f func(interface{}) interface{}, zeroValIsPresent ...ZeroValueIsPresentFlags) Optional {
+	if !o.present || !reflect.TypeOf(o.value).AssignableTo(typ) {
+		return Optional{}
+	}
+
+	return o.MapFunc(f, zeroValIsPresent...)
+}
+
+// MapIfType is the same as Map, except that if the wrapped value is not assignable to the parameter type of the mapping function,
+// this Optional is returned unchanged rather than an empty Optional, without calling the mapping function.
+// This allows a chain of type specific mapping functions to be applied to an Optional that may hold one of several types.
+// The mapping function must be a func of one parameter that returns one value, else a panic occurs.
+func (o Optional) MapIfType(f interface{}, zeroValIsPresent ...ZeroValueIsPresentFlags) Optional {
+	ft := reflect.TypeOf(f)
+	if (ft == nil) || (ft.Kind() != reflect.Func) || (ft.NumIn() != 1) || (ft.NumOut() != 1) {
+		panic(fmt.Sprintf("MapIfType requires a func of one parameter that returns one value, not %T", f))
+	}
+
+	if !o.present || !reflect.TypeOf(o.value).AssignableTo(ft.In(0)) {
+		return o
+	}
+
+	return o.Map(f, zeroValIsPresent...)
+}
+
+// MapMust is the same as Map, except that it panics if this Optional is not present.
+// This is useful for code paths that assume presence, where an empty Optional indicates a bug.
+func (o Optional) MapMust(f interface{}, zeroValIsPresent ...ZeroValueIsPresentFlags) Optional {
+	if !o.present {
+		panic(errNotPresent)
+	}
+
+	return o.Map(f, zeroValIsPresent...)
+}
+
+// MapAll applies Map to each Optional in the given slice, returning a new slice of the results.
+// Empty Optionals remain empty, and the given slice is not modified.
+// The mapping function and zero value flags are the same as for Map.
+func MapAll(opts []Optional, f interface{}, zeroValIsPresent ...ZeroValueIsPresentFlags) []Optional {
+	result := make([]Optional, len(opts))
+	for i, opt := range opts {
+		result[i] = opt.Map(f, zeroValIsPresent...)
+	}
+
+	return result
+}
+
+// Collect assigns the values of the given Optionals to the fields of the struct the target points to, in field order.
+// A present Optional is assigned to the corresponding field, while the field for an empty Optional is left untouched,
+// which allows assembling a partial update.
+// An error is returned if the target is not a pointer to a struct, the number of Optionals is not the same as the number of fields,
+// or a present value is not assignable to its field. In the case of an error, the target is not modified.
+func Collect(target interface{}, opts ...Optional) error {
+	rv := reflect.ValueOf(target)
+	if (rv.Kind() != reflect.Ptr) || rv.IsNil() || (rv.Elem().Kind() != reflect.Struct) {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, not %T", target)
+	}
+
+	rv = rv.Elem()
+	if rv.NumField() != len(opts) {
+		return fmt.Errorf("%s has %d fields, but %d Optionals were provided", rv.Type(), rv.NumField(), len(opts))
+	}
+
+	for i, opt := range opts {
+		if !opt.present {
+			continue
+		}
+
+		field := rv.Type().Field(i)
+		if !rv.Field(i).CanSet() {
+			return fmt.Errorf("field %s of %s cannot be set", field.Name, rv.Type())
+		}
+
+		if vt := reflect.TypeOf(opt.value); !vt.AssignableTo(field.Type) {
+			return fmt.Errorf("a value of type %s cannot be assigned to field %s of type %s", vt, field.Name, field.Type)
+		}
+	}
+
+	for i, opt := range opts {
+		if opt.present {
+			rv.Field(i).Set(reflect.ValueOf(opt.value))
+		}
+	}
+
+	return nil
+}
+
+// optionalTypes are the types of fields populated by PopulateOptionals
+var optionalTypes = map[reflect.Type]bool{
+	reflect.TypeOf(Optional{}):        true,
+	reflect.TypeOf(OptionalDecimal{}): true,
+	reflect.TypeOf(OptionalUUID{}):    true,
+}
+
+// PopulateOptionals sets each exported Optional, OptionalDecimal, and OptionalUUID field of the struct the target points to
+// from the value of the matching key of the given map, such as a decoded JSON object or a NoSQL row.
+// The key is the name given by an `optional:"name"` field tag if there is one, else the field name.
+// A tag of "-" skips the field, and fields of other types are ignored.
+// Each value is set with the Scan method of the field, so a nil value or an absent key results in an empty field,
+// and any converters registered with RegisterScanConverter apply to Optional fields.
+// An error is returned if the target is not a pointer to a struct, or a value cannot be scanned into its field,
+// in which case the error wraps the error returned by Scan.
+// In the case of an error, the target is not modified.
+func PopulateOptionals(target interface{}, m map[string]interface{}) error {
+	rv := reflect.ValueOf(target)
+	if (rv.Kind() != reflect.Ptr) || rv.IsNil() || (rv.Elem().Kind() != reflect.Struct) {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, not %T", target)
+	}
+
+	rv = rv.Elem()
+	result := reflect.New(rv.Type()).Elem()
+	result.Set(rv)
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if (field.PkgPath != "") || !optionalTypes[field.Type] {
+			continue
+		}
+
+		key := field.Name
+		if tag, haveIt := field.Tag.Lookup("optional"); haveIt {
+			if tag == "-" {
+				continue
+			}
+			key = tag
+		}
+
+		// An absent key is a nil value
+		if err := result.Field(i).Addr().Interface().(sql.Scanner).Scan(m[key]); err != nil {
+			return fmt.Errorf("field %s of %s cannot be populated from key %s: %w", field.Name, rv.Type(), key, err)
+		}
+	}
+
+	rv.Set(result)
+	return nil
+}
+
+// Reduce folds the values of the present Optionals in the given slice into an accumulator, skipping empty Optionals,
+// in the same way that SQL aggregates ignore NULL.
+// The accumulator starts as init, and is replaced by f(accumulator, value) for each present value in order.
+// If no Optional is present, init is returned.
+func Reduce(opts []Optional, init interface{}, f func(acc, val interface{}) interface{}) interface{} {
+	acc := init
+	for _, opt := range opts {
+		if opt.present {
+			acc = f(acc, opt.value)
+		}
+	}
+
+	return acc
+}
+
+// Partition splits the given slice of Optionals into the values of the present ones, in order, and a count of the empty ones.
+func Partition(opts []Optional) (present []interface{}, emptyCount int) {
+	for _, opt := range opts {
+		if opt.present {
+			present = append(present, opt.value)
+		} else {
+			emptyCount++
+		}
+	}
+
+	return present, emptyCount
+}
+
+// EqualValues returns true if the given slice of Optionals has the same length as the given values,
+// and each Optional is empty where the corresponding value is nil, else present with a reflect.DeepEqual value.
+// This simplifies test assertions over columns of nullable values.
+func EqualValues(opts []Optional, vals ...interface{}) bool {
+	if len(opts) != len(vals) {
+		return false
+	}
+
+	for i, opt := range opts {
+		if (opt.present != (vals[i] != nil)) || (opt.present && !reflect.DeepEqual(opt.value, vals[i])) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CountByValue tallies how many times each present value occurs in the given slice of Optionals, and counts the empty ones.
+// Comparable values are used as map keys as is. A value of a type that is not comparable, such as a slice or map,
+// cannot be a map key, so it is keyed by the string fmt.Sprintf("%#v", value) instead, eg []byte("x") is keyed by "[]byte{0x78}".
+// Note that values of different types are different keys, so 1 and int64(1) are counted separately.
+func CountByValue(opts []Optional) (counts map[interface{}]int, emptyCount int) {
+	counts = map[interface{}]int{}
+	for _, opt := range opts {
+		if opt.present {
+			key := opt.value
+			if !reflect.TypeOf(key).Comparable() {
+				key = fmt.Sprintf("%#v", key)
+			}
+			counts[key]++
+		} else {
+			emptyCount++
+		}
+	}
+
+	return counts, emptyCount
+}
+
 // FlatMap operates like Map, except that the mapping function already returns an Optional, which is returned as is.
 func (o Optional) FlatMap(f interface{}) Optional {
 	if !o.present {
@@ -152,30 +968,441 @@ func (o Optional) FlatMap(f interface{}) Optional {
 	return gofuncs.MapTo(f, Optional{}).(func(interface{}) Optional)(o.value)
 }
 
+// optionalType is the reflect.Type of Optional
+var optionalType = reflect.TypeOf(Optional{})
+
+// FlatMapErr is the same as FlatMap, except that instead of panicking when the mapping function has the wrong signature,
+// a descriptive error is returned.
+// The mapping function must be a func of one arg that returns an Optional.
+// If this Optional is present, its value must be convertible to the arg type, except that an integer is not converted to a string,
+// as for OrElseConvert.
+// If this Optional is empty, the signature is still validated, but the mapping function is not invoked.
+func (o Optional) FlatMapErr(f interface{}) (Optional, error) {
+	ft := reflect.TypeOf(f)
+	if (ft == nil) || (ft.Kind() != reflect.Func) || (ft.NumIn() != 1) || (ft.NumOut() != 1) || (ft.Out(0) != optionalType) {
+		return Optional{}, fmt.Errorf("a func of one arg that returns an Optional is required, not %v", ft)
+	}
+
+	if !o.present {
+		return Optional{}, nil
+	}
+
+	if vt, at := reflect.TypeOf(o.value), ft.In(0); !vt.ConvertibleTo(at) || ((at.Kind() == reflect.String) && isIntKind(vt.Kind())) {
+		return Optional{}, fmt.Errorf("a value of type %s cannot be passed to a %v", vt, ft)
+	}
+
+	return o.FlatMap(f), nil
+}
+
+// scanConverters is the registry of converters used by Scan, keyed by the type of value provided to Scan
+var scanConverters = struct {
+	sync.RWMutex
+	converters map[reflect.Type]func(interface{}) (interface{}, error)
+}{
+	converters: map[reflect.Type]func(interface{}) (interface{}, error){},
+}
+
+// RegisterScanConverter registers a function that Scan uses to convert a driver value of the given source type before storing it,
+// such as converting a numeric column that a driver provides as a string into an int.
+// The converter applies to all Optionals, so that driver specific representations are handled uniformly.
+// Registering a converter for a type that already has one replaces it, and registering a nil converter removes it.
+// Values of types without a converter are stored as is.
+// The registry is safe for concurrent use.
+func RegisterScanConverter(srcType reflect.Type, converter func(src interface{}) (interface{}, error)) {
+	scanConverters.Lock()
+	defer scanConverters.Unlock()
+
+	if converter == nil {
+		delete(scanConverters.converters, srcType)
+	} else {
+		scanConverters.converters[srcType] = converter
+	}
+}
+
 // Scan is database/sql Scanner interface, allowing users to read null query columns into an Optional.
-// This is the only method that modifies an Optional.
+// Along with the other decoding methods, this is the only kind of method that modifies an Optional.
 // The result will be same whether or not the Optional was initially empty.
 // The provided value is just stored, so if it is a reference type it must be copied before the next call to Scan.
-// Since any value can be stored, the result is always a nil error.
+// If the provided value is a sql.NullBool, NullFloat64, NullInt32, NullInt64, NullString, or NullTime, the inner value is stored if it is valid,
+// otherwise the Optional is empty.
+// If a converter is registered for the type of a non-nil value (see RegisterScanConverter), the converted value is stored instead.
+// Since any value can be stored, the result is a nil error unless a converter fails, in which case the Optional is unmodified.
 // It is up to the caller to ensure the correct type is being read.
 func (o *Optional) Scan(src interface{}) error {
+	valid := true
+	switch v := src.(type) {
+	case sql.NullBool:
+		src, valid = v.Bool, v.Valid
+	case sql.NullFloat64:
+		src, valid = v.Float64, v.Valid
+	case sql.NullInt32:
+		src, valid = v.Int32, v.Valid
+	case sql.NullInt64:
+		src, valid = v.Int64, v.Valid
+	case sql.NullString:
+		src, valid = v.String, v.Valid
+	case sql.NullTime:
+		src, valid = v.Time, v.Valid
+	}
+
+	if !valid {
+		src = nil
+	}
+
+	if src != nil {
+		scanConverters.RLock()
+		converter := scanConverters.converters[reflect.TypeOf(src)]
+		scanConverters.RUnlock()
+
+		if converter != nil {
+			var err error
+			if src, err = converter(src); err != nil {
+				return err
+			}
+		}
+	}
+
 	o.value = src
 	o.present = !gofuncs.IsNil(src)
 	return nil
 }
 
+// ScanText scans a text value, such as a CSV field, where the given token represents null.
+// If the text equals the token, the Optional becomes empty, otherwise it is the same as Scan(text).
+// An empty text is a present empty string, unless the token is also empty.
+func (o *Optional) ScanText(text string, nullToken string) error {
+	if text == nullToken {
+		return o.Scan(nil)
+	}
+
+	return o.Scan(text)
+}
+
+// ScanStrict is the same as Scan, except that it returns an error without modifying the Optional
+// if the provided value is a kind that can never come from a database driver: a chan, func, complex number, or unsafe pointer.
+// This catches code that mistakenly wires something other than a column to an Optional.
+func (o *Optional) ScanStrict(src interface{}) error {
+	if src != nil {
+		switch kind := reflect.TypeOf(src).Kind(); kind {
+		case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+			return fmt.Errorf("cannot scan a value of kind %s", kind)
+		}
+	}
+
+	return o.Scan(src)
+}
+
+// ScanDelta is the same as Scan, and also returns true if the scan changed whether or not the Optional is present.
+// This allows detecting NULL to value and value to NULL transitions when reusing an Optional across rows.
+func (o *Optional) ScanDelta(src interface{}) (bool, error) {
+	wasPresent := o.present
+	err := o.Scan(src)
+	return o.present != wasPresent, err
+}
+
+// OnlyChangedScan is the same as Scan, except that the Optional is only modified if the scanned result has Changed from the current state.
+// When reusing an Optional across many rows of a mostly unchanging column, this keeps the existing value rather than
+// storing a new reference for each row, and the result is true only if the Optional was modified.
+// If Scan would fail, the error is returned and the Optional is unmodified.
+func (o *Optional) OnlyChangedScan(src interface{}) (bool, error) {
+	var scanned Optional
+	if err := scanned.Scan(src); err != nil {
+		return false, err
+	}
+
+	if !Changed(*o, scanned) {
+		return false, nil
+	}
+
+	// Redaction is a property of the receiver, not the scanned value
+	o.value, o.present = scanned.value, scanned.present
+	return true, nil
+}
+
+// Rows is the subset of the methods of *sql.Rows that are used by ScanAll
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// ScanAll reads every remaining row of a single column result set into a slice of Optionals, where a NULL column is an empty Optional.
+// Typically rows is a *sql.Rows, which the caller remains responsible for closing.
+// If a Scan fails or rows.Err is not nil after iterating, the Optionals read so far and the error are returned.
+func ScanAll(rows Rows) ([]Optional, error) {
+	var result []Optional
+	for rows.Next() {
+		var opt Optional
+		if err := rows.Scan(&opt); err != nil {
+			return result, err
+		}
+
+		result = append(result, opt)
+	}
+
+	return result, rows.Err()
+}
+
 // Value is the database/sql/driver/Valuer interface, allowing users to write an Optional into a column.
-// If a present optional does not contain an allowed type, the operation will fail.
+// A present value of a sized int, uint, or float kind, such as int16, uint32, or float32, is normalized to an int64 or float64,
+// so that scanning the result produces an Optional with the same value a driver would provide.
+// A wrapped driver.Valuer provides its own value.
+// Any other value is returned unchanged, so that the driver decides whether it can be written,
+// such as a driver that accepts slices or maps through a driver.NamedValueChecker.
 // It is up to the caller to ensure the correct type is being written.
 func (o Optional) Value() (driver.Value, error) {
+	if !o.present {
+		return nil, nil
+	}
+
+	if valuer, isa := o.value.(driver.Valuer); isa {
+		return valuer.Value()
+	}
+
+	switch rv := reflect.ValueOf(o.value); rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return rv.Int(), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(rv.Uint()), nil
+	case reflect.Float32:
+		return rv.Float(), nil
+	}
+
+	return o.value, nil
+}
+
+// gobOptional is the form of an Optional that is encoded by gob
+type gobOptional struct {
+	Value   interface{}
+	Present bool
+}
+
+// RegisterGob registers the concrete types of the given values with gob, so that Optionals wrapping them can be gob encoded and decoded.
+// Since the wrapped value of an Optional is an interface{}, gob requires its concrete type to be registered.
+// Builtin types such as bool, numbers, strings, and slices of them are registered by gob already, so they do not need to be registered.
+// Any other type must be registered by the same name in both encoding and decoding processes, such as user defined structs.
+func RegisterGob(values ...interface{}) {
+	for _, value := range values {
+		gob.Register(value)
+	}
+}
+
+// GobEncode is the gob.GobEncoder interface, allowing users to gob encode an Optional.
+// The concrete type of the wrapped value must be registered with gob, see RegisterGob.
+func (o Optional) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gobOptional{Value: o.value, Present: o.present}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode is the gob.GobDecoder interface, allowing users to gob decode an Optional.
+// The result will be same whether or not the Optional was initially empty.
+// As for Of, a nil value decodes as an empty Optional, even if the data claims it is present.
+func (o *Optional) GobDecode(data []byte) error {
+	var g gobOptional
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		return err
+	}
+
+	if g.Present && !gofuncs.IsNil(g.Value) {
+		o.value = g.Value
+		o.present = true
+	} else {
+		o.value = nil
+		o.present = false
+	}
+	return nil
+}
+
+// AsString returns an Optional of the ValueString of the wrapped value if it is present, else a new empty Optional.
+// This never fails, since any value can be formatted as a string.
+func (o Optional) AsString() Optional {
+	if !o.present {
+		return Optional{}
+	}
+
+	return Optional{value: o.ValueString(), present: true}
+}
+
+// ValueString returns a string of only the wrapped value, without the "Optional (...)" wrapper that String adds.
+// If the value implements fmt.Stringer, the result of its String method is returned, else fmt.Sprintf("%v", value).
+// An empty string is returned if the Optional is empty.
+// Unlike String, the result is not affected by SetValueFormatter.
+func (o Optional) ValueString() string {
+	if !o.present {
+		return ""
+	}
+
+	if str, isa := o.value.(fmt.Stringer); isa {
+		return str.String()
+	}
+
+	return fmt.Sprintf("%v", o.value)
+}
+
+// AddTo adds the wrapped value to the given url.Values under the given key, only if the value is present.
+// The value is added as a string by ValueString, so a present empty string is added as an empty value.
+// If the Optional is empty, the url.Values are not modified, leaving the key absent.
+func (o Optional) AddTo(values url.Values, key string) {
 	if o.present {
-		return o.value, nil
+		values.Add(key, o.ValueString())
+	}
+}
+
+// SQLLiteral returns the value as a SQL literal, for logging or building debug queries, never for executing them.
+// Parameterized queries must be used to execute SQL, as they do not depend on the quoting rules of a particular database.
+// An empty Optional is NULL. Otherwise the value is converted as by Value, and rendered as follows:
+// an int or uint of any size or a float is a bare number, a bool is TRUE or FALSE, a time.Time is a quoted RFC 3339 string,
+// and a string or []byte is quoted with single quotes, where any embedded single quote is doubled.
+// A float64 that is not finite has no bare form, so it is quoted as 'NaN', 'Infinity', or '-Infinity', as accepted by PostgreSQL.
+// A present value that converts to nil, such as a sql.NullString that is not valid, is NULL.
+// A value that Value cannot convert is rendered as a quoted ValueString.
+// A present Redacted Optional is rendered as '****', so that the secret is not written to logs.
+func (o Optional) SQLLiteral() string {
+	if !o.present {
+		return "NULL"
 	}
 
-	return nil, nil
+	if o.redacted {
+		return sqlQuote("****")
+	}
+
+	val, err := o.Value()
+	if err != nil {
+		val = o.ValueString()
+	}
+
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return sqlQuote("NaN")
+		case math.IsInf(v, 1):
+			return sqlQuote("Infinity")
+		case math.IsInf(v, -1):
+			return sqlQuote("-Infinity")
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return gofuncs.Ternary(v, "TRUE", "FALSE").(string)
+	case time.Time:
+		return sqlQuote(v.Format(time.RFC3339Nano))
+	case []byte:
+		return sqlQuote(string(v))
+	case string:
+		return sqlQuote(v)
+	default:
+		return sqlQuote(o.ValueString())
+	}
+}
+
+// sqlQuote returns the string in single quotes, doubling any embedded single quotes
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
 }
 
-// String returns fmt.Sprintf("Optional (%v)", wrapped value) if present, else "Optional" if it is empty.
+// valueFormatter holds the func(interface{}) string that String uses to render a present value
+var valueFormatter atomic.Value
+
+// defaultValueFormatter renders a value with fmt.Sprintf("%v", value)
+func defaultValueFormatter(value interface{}) string {
+	return fmt.Sprintf("%v", value)
+}
+
+func init() {
+	valueFormatter.Store(defaultValueFormatter)
+}
+
+// SetValueFormatter sets the function that String uses to render present values, such as to redact secrets in logs.
+// A nil formatter restores the default of fmt.Sprintf("%v", value).
+// An empty Optional is always rendered as "Optional", and only String is affected, so ValueString and GoString still render the actual value.
+// It is safe to call concurrently with String, but is intended to be called once at startup.
+func SetValueFormatter(formatter func(interface{}) string) {
+	if formatter == nil {
+		formatter = defaultValueFormatter
+	}
+
+	valueFormatter.Store(formatter)
+}
+
+// String returns "Optional (formatted value)" if present, else "Optional" if it is empty.
+// By default the value is formatted with fmt.Sprintf("%v", wrapped value), see SetValueFormatter.
+// If the Optional is present and Redacted, "Optional (****)" is returned.
 func (o Optional) String() string {
-	return gofuncs.Ternary(o.present, fmt.Sprintf("Optional (%v)", o.value), emptyString).(string)
+	if !o.present {
+		return emptyString
+	}
+
+	if o.redacted {
+		return redactedString
+	}
+
+	return fmt.Sprintf("Optional (%s)", valueFormatter.Load().(func(interface{}) string)(o.value))
+}
+
+// GoString is the fmt.GoStringer interface, used when formatting with %#v.
+// It returns fmt.Sprintf("Optional (%#v)", wrapped value) if present, else "Optional" if it is empty.
+// This renders structs with their type and field names, which is useful for debugging.
+// If the Optional is present and Redacted, "Optional (****)" is returned.
+func (o Optional) GoString() string {
+	if o.present && o.redacted {
+		return redactedString
+	}
+
+	return gofuncs.Ternary(o.present, fmt.Sprintf("Optional (%#v)", o.value), emptyString).(string)
+}
+
+// optionalFields has the same fields as Optional without any methods, so that it is formatted as a plain struct
+type optionalFields Optional
+
+// Format is the fmt.Formatter interface, so that a Redacted Optional cannot leak its value through any verb.
+// If the Optional is present and Redacted, "Optional (****)" is written for every verb, ignoring flags, width, and precision.
+// Otherwise the result is the same as if Optional did not implement fmt.Formatter:
+// %#v uses GoString, the verbs v, s, x, X, and q format the result of String, and any other verb formats the struct fields.
+func (o Optional) Format(f fmt.State, verb rune) {
+	if o.present && o.redacted {
+		fmt.Fprint(f, redactedString)
+		return
+	}
+
+	if (verb == 'v') && f.Flag('#') {
+		fmt.Fprint(f, o.GoString())
+		return
+	}
+
+	// Rebuild the format directive from the state, and apply it to either the String result or the fields
+	var directive strings.Builder
+	directive.WriteRune('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive.WriteRune(flag)
+		}
+	}
+	if width, haveIt := f.Width(); haveIt {
+		directive.WriteString(strconv.Itoa(width))
+	}
+	if prec, haveIt := f.Precision(); haveIt {
+		directive.WriteString("." + strconv.Itoa(prec))
+	}
+	directive.WriteRune(verb)
+
+	switch verb {
+	case 'v', 's', 'x', 'X', 'q':
+		fmt.Fprintf(f, directive.String(), o.String())
+	default:
+		fmt.Fprintf(f, directive.String(), optionalFields(o))
+	}
 }
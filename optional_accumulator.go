@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"strings"
+)
+
+// StringAccumulator is a mutable accumulator of string parts, such as those seen while parsing a stream,
+// that builds an Optional which is empty if nothing was appended.
+// This keeps "nothing seen" distinct from "an empty string seen".
+// The zero value is ready to use.
+type StringAccumulator struct {
+	builder  strings.Builder
+	appended bool
+}
+
+// Append adds a part to the accumulated string. Appending an empty string still counts as having seen a value.
+func (a *StringAccumulator) Append(s string) {
+	a.builder.WriteString(s)
+	a.appended = true
+}
+
+// Build returns an empty Optional if nothing has been appended, else an Optional of the concatenation of all parts appended so far.
+// The accumulator is not reset, so further parts may be appended and built again.
+func (a *StringAccumulator) Build() Optional {
+	if !a.appended {
+		return Optional{}
+	}
+
+	return Optional{value: a.builder.String(), present: true}
+}
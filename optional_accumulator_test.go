@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringAccumulator(t *testing.T) {
+	var a StringAccumulator
+	assert.True(t, a.Build().IsEmpty())
+
+	a.Append("")
+	assert.Equal(t, Of(""), a.Build())
+
+	a.Append("a")
+	assert.Equal(t, Of("a"), a.Build())
+
+	a.Append("b")
+	a.Append("c")
+	assert.Equal(t, Of("abc"), a.Build())
+}
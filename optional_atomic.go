@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"sync/atomic"
+)
+
+// AtomicOptional holds an Optional that can be read and replaced by many goroutines without a mutex.
+// Reads are lock free, and writes are atomic swaps of the whole Optional, so a reader never sees a partially written value.
+// It is intended for values that are read often and replaced occasionally, such as configuration.
+// The zero value is ready to use, and holds an empty Optional.
+// An AtomicOptional must not be copied after first use.
+type AtomicOptional struct {
+	value atomic.Value
+}
+
+// Load returns the Optional most recently stored, or an empty Optional if none has been stored.
+func (a *AtomicOptional) Load() Optional {
+	if opt, isa := a.value.Load().(Optional); isa {
+		return opt
+	}
+
+	return Optional{}
+}
+
+// Store atomically replaces the Optional held.
+func (a *AtomicOptional) Store(opt Optional) {
+	a.value.Store(opt)
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicOptionalLoadStore(t *testing.T) {
+	var a AtomicOptional
+	assert.Equal(t, Of(), a.Load())
+
+	a.Store(Of("a"))
+	assert.Equal(t, Of("a"), a.Load())
+
+	a.Store(Of())
+	assert.Equal(t, Of(), a.Load())
+}
+
+func TestAtomicOptionalConcurrent(t *testing.T) {
+	var (
+		a  AtomicOptional
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				a.Store(Of(i*100 + j))
+			}
+		}(i)
+	}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if opt := a.Load(); opt.IsPresent() {
+					val := opt.MustGet().(int)
+					assert.True(t, (val >= 0) && (val < 400))
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.True(t, a.Load().IsPresent())
+}
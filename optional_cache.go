@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultOrElseCacheSize is the default maximum number of entries in the OrElseGetCached cache
+	DefaultOrElseCacheSize = 128
+
+	// errOrElseCacheSupplierPanicked is the panic message for callers that waited on a supplier that panicked
+	errOrElseCacheSupplierPanicked = "OrElseGetCached supplier panicked"
+)
+
+// orElseCacheEntry is a single cached supplier result
+type orElseCacheEntry struct {
+	key    string
+	value  interface{}
+	stored time.Time
+}
+
+// orElseCacheCall is a supplier call in progress, that other callers for the same key wait on
+type orElseCacheCall struct {
+	wg    sync.WaitGroup
+	value interface{}
+	ok    bool
+}
+
+// orElseCache is a least recently used cache of supplier results, used by OrElseGetCached.
+// All access is guarded by the mutex, which is never held while a supplier is running.
+// The generation is incremented by ClearOrElseCache, so that a supplier call in progress when the cache is cleared is not cached.
+var orElseCache = struct {
+	sync.Mutex
+	maxSize    int
+	ttl        time.Duration
+	generation uint64
+	entries    map[string]*list.Element
+	order      *list.List
+	inFlight   map[string]*orElseCacheCall
+}{
+	maxSize:  DefaultOrElseCacheSize,
+	entries:  map[string]*list.Element{},
+	order:    list.New(),
+	inFlight: map[string]*orElseCacheCall{},
+}
+
+// orElseCacheNow returns the current time, and is a var so that tests can control expiry
+var orElseCacheNow = time.Now
+
+// evictOrElseCache removes least recently used entries until the cache is no larger than the max size.
+// The caller must hold the lock.
+func evictOrElseCache() {
+	for orElseCache.order.Len() > orElseCache.maxSize {
+		delete(orElseCache.entries, orElseCache.order.Remove(orElseCache.order.Back()).(orElseCacheEntry).key)
+	}
+}
+
+// SetOrElseCacheSize sets the maximum number of entries in the OrElseGetCached cache, evicting the least recently used entries if needed.
+// A size of zero disables caching, so that suppliers are always called.
+// A negative size is treated as zero.
+func SetOrElseCacheSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+
+	orElseCache.Lock()
+	defer orElseCache.Unlock()
+
+	orElseCache.maxSize = size
+	evictOrElseCache()
+}
+
+// SetOrElseCacheTTL sets how long entries of the OrElseGetCached cache remain valid after they are stored.
+// An expired entry is discarded when it is next looked up, so that the supplier is called again.
+// The TTL applies to all entries, including those already stored.
+// A TTL of zero, the default, means entries never expire. A negative TTL is treated as zero.
+func SetOrElseCacheTTL(ttl time.Duration) {
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	orElseCache.Lock()
+	defer orElseCache.Unlock()
+
+	orElseCache.ttl = ttl
+}
+
+// ClearOrElseCache removes all entries from the OrElseGetCached cache, without changing the maximum size or TTL.
+// The results of supplier calls in progress are returned to their callers, but are not cached.
+func ClearOrElseCache() {
+	orElseCache.Lock()
+	defer orElseCache.Unlock()
+
+	orElseCache.entries = map[string]*list.Element{}
+	orElseCache.order.Init()
+	orElseCache.generation++
+}
+
+// OrElseGetCached returns the wrapped value if it is present, else it returns the result of the given function,
+// which is cached under the given key so that subsequent calls with the same key do not call a supplier again.
+// supplier must be a func of no args that returns a single value, as for OrElseGet.
+//
+// The cache is shared by all Optionals, and holds up to DefaultOrElseCacheSize entries unless changed by SetOrElseCacheSize.
+// When full, the least recently used entry is evicted. Entries never expire unless a TTL is set by SetOrElseCacheTTL.
+// Use ClearOrElseCache to discard all entries.
+//
+// The cache is safe for concurrent use. Suppliers are called without holding the cache lock,
+// so a slow supplier only delays callers waiting for the same key, while other keys and cache hits proceed.
+// Concurrent callers for a key that is not cached wait for a single supplier call and share its result.
+// If that supplier panics, the panic propagates to its caller, the waiting callers panic as well, and nothing is cached.
+// A supplier may call OrElseGetCached for other keys, but not for its own key, which would wait forever.
+func (o Optional) OrElseGetCached(key string, supplier interface{}) interface{} {
+	if o.present {
+		return o.value
+	}
+
+	orElseCache.Lock()
+
+	if elem, haveIt := orElseCache.entries[key]; haveIt {
+		entry := elem.Value.(orElseCacheEntry)
+		if (orElseCache.ttl == 0) || (orElseCacheNow().Sub(entry.stored) < orElseCache.ttl) {
+			orElseCache.order.MoveToFront(elem)
+			orElseCache.Unlock()
+			return entry.value
+		}
+
+		// Expired
+		orElseCache.order.Remove(elem)
+		delete(orElseCache.entries, key)
+	}
+
+	if call, haveIt := orElseCache.inFlight[key]; haveIt {
+		orElseCache.Unlock()
+		call.wg.Wait()
+
+		if !call.ok {
+			panic(errOrElseCacheSupplierPanicked)
+		}
+
+		return call.value
+	}
+
+	call := &orElseCacheCall{}
+	call.wg.Add(1)
+	orElseCache.inFlight[key] = call
+	generation := orElseCache.generation
+	orElseCache.Unlock()
+
+	defer func() {
+		orElseCache.Lock()
+		delete(orElseCache.inFlight, key)
+		if call.ok && (orElseCache.maxSize > 0) && (orElseCache.generation == generation) {
+			orElseCache.entries[key] = orElseCache.order.PushFront(orElseCacheEntry{key: key, value: call.value, stored: orElseCacheNow()})
+			evictOrElseCache()
+		}
+		orElseCache.Unlock()
+
+		call.wg.Done()
+	}()
+
+	call.value = o.OrElseGet(supplier)
+	call.ok = true
+
+	return call.value
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionalOrElseGetCached(t *testing.T) {
+	defer SetOrElseCacheSize(DefaultOrElseCacheSize)
+	defer ClearOrElseCache()
+
+	calls := 0
+	supplier := func() int {
+		calls++
+		return calls
+	}
+
+	// Present values do not call the supplier or populate the cache
+	assert.Equal(t, 5, Of(5).OrElseGetCached("a", supplier))
+	assert.Equal(t, 0, calls)
+
+	// The supplier runs once across multiple calls
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, 1, Of().OrElseGetCached("a", supplier))
+	}
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, 2, Of().OrElseGetCached("b", supplier))
+	assert.Equal(t, 1, Of().OrElseGetCached("a", supplier))
+	assert.Equal(t, 2, calls)
+
+	// Clearing forces the supplier to run again
+	ClearOrElseCache()
+	assert.Equal(t, 3, Of().OrElseGetCached("a", supplier))
+	assert.Equal(t, 3, calls)
+
+	// Shrinking evicts the least recently used entries
+	assert.Equal(t, 4, Of().OrElseGetCached("b", supplier))
+	assert.Equal(t, 3, Of().OrElseGetCached("a", supplier))
+	SetOrElseCacheSize(1)
+	assert.Equal(t, 3, Of().OrElseGetCached("a", supplier))
+	assert.Equal(t, 5, Of().OrElseGetCached("b", supplier))
+	assert.Equal(t, 6, Of().OrElseGetCached("a", supplier))
+	assert.Equal(t, 6, calls)
+
+	// A size of zero disables caching
+	SetOrElseCacheSize(-1)
+	assert.Equal(t, 7, Of().OrElseGetCached("a", supplier))
+	assert.Equal(t, 8, Of().OrElseGetCached("a", supplier))
+}
+
+func TestOptionalOrElseGetCachedConcurrent(t *testing.T) {
+	defer ClearOrElseCache()
+
+	var (
+		mu    sync.Mutex
+		calls = 0
+		wg    sync.WaitGroup
+	)
+
+	supplier := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls++
+		return "default"
+	}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "default", Of().OrElseGetCached("concurrent", supplier))
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 1, calls)
+}
+
+func TestOptionalOrElseGetCachedSlowSupplier(t *testing.T) {
+	defer ClearOrElseCache()
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		results = make(chan interface{}, 2)
+		calls   = 0
+		slow    = func() string {
+			calls++
+			close(started)
+			<-release
+			return "slow"
+		}
+	)
+
+	go func() { results <- Of().OrElseGetCached("slow", slow) }()
+	<-started
+
+	// A second caller for the same key waits for the call in progress
+	go func() { results <- Of().OrElseGetCached("slow", slow) }()
+
+	// Other keys are not blocked by the slow supplier
+	done := make(chan interface{})
+	go func() { done <- Of().OrElseGetCached("fast", func() string { return "fast" }) }()
+	select {
+	case val := <-done:
+		assert.Equal(t, "fast", val)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "a slow supplier blocked another key")
+	}
+
+	close(release)
+	assert.Equal(t, "slow", <-results)
+	assert.Equal(t, "slow", <-results)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "slow", Of().OrElseGetCached("slow", slow))
+}
+
+func TestOptionalOrElseGetCachedPanic(t *testing.T) {
+	defer ClearOrElseCache()
+
+	func() {
+		defer func() {
+			assert.Equal(t, "failed", recover())
+		}()
+
+		Of().OrElseGetCached("panic", func() int { panic("failed") })
+		assert.Fail(t, "Expected Panic")
+	}()
+
+	// Nothing was cached
+	assert.Equal(t, 1, Of().OrElseGetCached("panic", func() int { return 1 }))
+}
+
+func TestOptionalOrElseGetCachedTTL(t *testing.T) {
+	defer SetOrElseCacheTTL(0)
+	defer ClearOrElseCache()
+	defer func() { orElseCacheNow = time.Now }()
+
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	orElseCacheNow = func() time.Time { return now }
+
+	calls := 0
+	supplier := func() int {
+		calls++
+		return calls
+	}
+
+	// No expiry by default
+	assert.Equal(t, 1, Of().OrElseGetCached("ttl", supplier))
+	now = now.Add(24 * time.Hour)
+	assert.Equal(t, 1, Of().OrElseGetCached("ttl", supplier))
+
+	// Entries expire once they are as old as the TTL
+	SetOrElseCacheTTL(time.Minute)
+	assert.Equal(t, 2, Of().OrElseGetCached("ttl", supplier))
+	now = now.Add(59 * time.Second)
+	assert.Equal(t, 2, Of().OrElseGetCached("ttl", supplier))
+	now = now.Add(time.Second)
+	assert.Equal(t, 3, Of().OrElseGetCached("ttl", supplier))
+
+	// A negative TTL is treated as zero
+	SetOrElseCacheTTL(-1)
+	now = now.Add(time.Hour)
+	assert.Equal(t, 3, Of().OrElseGetCached("ttl", supplier))
+}
@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// OptionalDecimal is an immutable wrapper for an exact decimal value, stored as a *big.Rat.
+// A nil *big.Rat is empty.
+// Since a *big.Rat is mutable, it is copied on construction and whenever it is returned.
+// The only mutable operations are the implementations of the sql.Scanner and encoding.BinaryUnmarshaler interfaces.
+// The zero value is ready to use.
+type OptionalDecimal struct {
+	value *big.Rat
+}
+
+var (
+	big2 = big.NewInt(2)
+	big5 = big.NewInt(5)
+)
+
+// OfDecimal returns an OptionalDecimal.
+// If no value or a nil value is provided, a new empty OptionalDecimal is returned.
+// Otherwise a new OptionalDecimal that wraps a copy of the value is returned.
+func OfDecimal(value ...*big.Rat) OptionalDecimal {
+	if (len(value) == 0) || (value[0] == nil) {
+		return OptionalDecimal{}
+	}
+
+	return OptionalDecimal{value: new(big.Rat).Set(value[0])}
+}
+
+// OfDecimalString returns an OptionalDecimal of the given string, which may be any form accepted by big.Rat.SetString.
+// An empty string results in an empty OptionalDecimal.
+// An error is returned if the string cannot be parsed.
+func OfDecimalString(value string) (OptionalDecimal, error) {
+	if value == "" {
+		return OptionalDecimal{}, nil
+	}
+
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return OptionalDecimal{}, fmt.Errorf("cannot parse %q as a decimal", value)
+	}
+
+	return OptionalDecimal{value: r}, nil
+}
+
+// Get returns a copy of the wrapped value and whether or not it is present.
+// The wrapped value is only valid if the boolean is true.
+func (o OptionalDecimal) Get() (*big.Rat, bool) {
+	if o.value == nil {
+		return nil, false
+	}
+
+	return new(big.Rat).Set(o.value), true
+}
+
+// MustGet returns a copy of the unwrapped value and panics if it is not present.
+func (o OptionalDecimal) MustGet() *big.Rat {
+	if o.value == nil {
+		panic(errNotPresent)
+	}
+
+	return new(big.Rat).Set(o.value)
+}
+
+// AsOptional returns a generic Optional wrapping a copy of the value if present, else an empty Optional.
+func (o OptionalDecimal) AsOptional() Optional {
+	if o.value == nil {
+		return Optional{}
+	}
+
+	return Optional{value: new(big.Rat).Set(o.value), present: true}
+}
+
+// ToOptionalDecimal converts a generic Optional into an OptionalDecimal, returning true if the conversion succeeded.
+// The conversion succeeds if the Optional is empty, resulting in an empty OptionalDecimal,
+// or if it is present and holds exactly a *big.Rat, resulting in an OptionalDecimal of a copy of the value.
+// If the Optional holds any other type, an empty OptionalDecimal and false are returned.
+func ToOptionalDecimal(opt Optional) (OptionalDecimal, bool) {
+	if !opt.present {
+		return OptionalDecimal{}, true
+	}
+
+	if r, isa := opt.value.(*big.Rat); isa {
+		return OfDecimal(r), true
+	}
+
+	return OptionalDecimal{}, false
+}
+
+// IsEmpty returns true if this OptionalDecimal is not present
+func (o OptionalDecimal) IsEmpty() bool {
+	return o.value == nil
+}
+
+// IsPresent returns true if this OptionalDecimal is present
+func (o OptionalDecimal) IsPresent() bool {
+	return o.value != nil
+}
+
+// Equal returns true if both OptionalDecimals are empty, or both are present with numerically equal values.
+// Values are compared with big.Rat.Cmp, so 1.5 and 1.50 are equal.
+func (o OptionalDecimal) Equal(opt OptionalDecimal) bool {
+	if (o.value == nil) || (opt.value == nil) {
+		return (o.value == nil) && (opt.value == nil)
+	}
+
+	return o.value.Cmp(opt.value) == 0
+}
+
+// Max returns the larger of the two OptionalDecimals, treating an empty OptionalDecimal as no constraint.
+// If only one is present, it is returned, and if both are empty, an empty OptionalDecimal is returned.
+func (o OptionalDecimal) Max(opt OptionalDecimal) OptionalDecimal {
+	if (o.value == nil) || ((opt.value != nil) && (opt.value.Cmp(o.value) > 0)) {
+		return opt
+	}
+
+	return o
+}
+
+// Min returns the smaller of the two OptionalDecimals, treating an empty OptionalDecimal as no constraint.
+// If only one is present, it is returned, and if both are empty, an empty OptionalDecimal is returned.
+func (o OptionalDecimal) Min(opt OptionalDecimal) OptionalDecimal {
+	if (o.value == nil) || ((opt.value != nil) && (opt.value.Cmp(o.value) < 0)) {
+		return opt
+	}
+
+	return o
+}
+
+// Fingerprint returns a stable FNV-1a hash of whether this OptionalDecimal is present and its value, for keying caches and bloom filters.
+// The value is hashed in lowest terms, so that equal values such as 1.5 and 1.50 have the same fingerprint,
+// and a leading presence byte keeps an empty OptionalDecimal distinct from a present zero.
+// Different values may collide, so it must not be used where an adversary chooses the values.
+func (o OptionalDecimal) Fingerprint() uint64 {
+	h := fnv.New64a()
+	if o.value == nil {
+		h.Write([]byte{0})
+		return h.Sum64()
+	}
+
+	// A big.Rat is always normalized to lowest terms, so equal values have the same RatString
+	h.Write([]byte{1})
+	h.Write([]byte(o.value.RatString()))
+	return h.Sum64()
+}
+
+// arith applies an arithmetic operation to the values of both OptionalDecimals, propagating empty like SQL NULL
+func (o OptionalDecimal) arith(opt OptionalDecimal, op func(z, x, y *big.Rat) *big.Rat) OptionalDecimal {
+	if (o.value == nil) || (opt.value == nil) {
+		return OptionalDecimal{}
+	}
+
+	return OptionalDecimal{value: op(new(big.Rat), o.value, opt.value)}
+}
+
+// Add returns an OptionalDecimal of the sum of both values if both are present, else an empty OptionalDecimal.
+func (o OptionalDecimal) Add(opt OptionalDecimal) OptionalDecimal {
+	return o.arith(opt, (*big.Rat).Add)
+}
+
+// Sub returns an OptionalDecimal of this value minus the given value if both are present, else an empty OptionalDecimal.
+func (o OptionalDecimal) Sub(opt OptionalDecimal) OptionalDecimal {
+	return o.arith(opt, (*big.Rat).Sub)
+}
+
+// Mul returns an OptionalDecimal of the product of both values if both are present, else an empty OptionalDecimal.
+func (o OptionalDecimal) Mul(opt OptionalDecimal) OptionalDecimal {
+	return o.arith(opt, (*big.Rat).Mul)
+}
+
+// decimalString returns the canonical decimal string of a rational number, and true if it has a finite decimal representation.
+// The canonical form uses only as many fractional digits as needed, so 3/2 is "1.5" and 2/1 is "2".
+// If the representation is not finite (eg 1/3), the fractional form (eg "1/3") and false are returned.
+func decimalString(r *big.Rat) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+
+	// A fraction in lowest terms is a finite decimal only if the denominator is of the form 2^m * 5^n,
+	// in which case max(m, n) fractional digits are required.
+	var (
+		d      = new(big.Int).Set(r.Denom())
+		q, rem = new(big.Int), new(big.Int)
+		twos   = 0
+		fives  = 0
+	)
+
+	for q.QuoRem(d, big2, rem); rem.Sign() == 0; q.QuoRem(d, big2, rem) {
+		d.Set(q)
+		twos++
+	}
+
+	for q.QuoRem(d, big5, rem); rem.Sign() == 0; q.QuoRem(d, big5, rem) {
+		d.Set(q)
+		fives++
+	}
+
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return r.RatString(), false
+	}
+
+	if fives > twos {
+		twos = fives
+	}
+
+	return r.FloatString(twos), true
+}
+
+// Scan is database/sql Scanner interface, allowing users to read null numeric columns into an OptionalDecimal.
+// This is the only method that modifies an OptionalDecimal.
+// A nil src results in an empty OptionalDecimal.
+// A string or []byte src is parsed as by OfDecimalString, and an int64 src is stored exactly.
+// A float64 src, such as a number decoded by encoding/json, is stored as the shortest decimal that formats as the same float64,
+// so that 1.1 is stored as 11/10 rather than the exact binary value. NaN and infinities result in an error.
+// Any other type of src results in an error, and the OptionalDecimal is unmodified.
+func (o *OptionalDecimal) Scan(src interface{}) error {
+	var r *big.Rat
+
+	switch v := src.(type) {
+	case nil:
+	case string:
+		opt, err := OfDecimalString(v)
+		if err != nil {
+			return err
+		}
+		r = opt.value
+	case []byte:
+		opt, err := OfDecimalString(string(v))
+		if err != nil {
+			return err
+		}
+		r = opt.value
+	case int64:
+		r = new(big.Rat).SetInt64(v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("cannot scan %v as a decimal", v)
+		}
+		r, _ = new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+	default:
+		return fmt.Errorf("cannot scan a value of type %T", src)
+	}
+
+	o.value = r
+	return nil
+}
+
+// ScanDelta is the same as Scan, and also returns true if the scan changed whether or not the OptionalDecimal is present.
+// If Scan fails, the OptionalDecimal is unmodified, so the result is false.
+func (o *OptionalDecimal) ScanDelta(src interface{}) (bool, error) {
+	wasPresent := o.value != nil
+	err := o.Scan(src)
+	return (o.value != nil) != wasPresent, err
+}
+
+// MarshalBinary is the encoding.BinaryMarshaler interface, with a compact form for caching and IPC that is smaller than gob.
+// An empty OptionalDecimal is a single 0 byte.
+// A present OptionalDecimal is a 1 byte, followed by the uvarint length and bytes of the value in lowest terms, such as 3/2,
+// so that a value with no finite decimal representation such as 1/3 is encoded exactly.
+func (o OptionalDecimal) MarshalBinary() ([]byte, error) {
+	if o.value == nil {
+		return []byte{0}, nil
+	}
+
+	var (
+		str    = o.value.RatString()
+		length [binary.MaxVarintLen64]byte
+		n      = binary.PutUvarint(length[:], uint64(len(str)))
+	)
+
+	return append(append([]byte{1}, length[:n]...), str...), nil
+}
+
+// UnmarshalBinary is the encoding.BinaryUnmarshaler interface, decoding the form produced by MarshalBinary.
+// If the data is not in that form, an error is returned and the OptionalDecimal is unmodified.
+func (o *OptionalDecimal) UnmarshalBinary(data []byte) error {
+	switch {
+	case (len(data) == 1) && (data[0] == 0):
+		o.value = nil
+		return nil
+	case (len(data) > 1) && (data[0] == 1):
+		if length, n := binary.Uvarint(data[1:]); (n > 0) && (length == uint64(len(data)-1-n)) {
+			if r, ok := new(big.Rat).SetString(string(data[1+n:])); ok {
+				o.value = r
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("cannot unmarshal %d bytes as a binary decimal", len(data))
+}
+
+// Value is the database/sql/driver/Valuer interface, allowing users to write an OptionalDecimal into a column.
+// If present, the value is written as a canonical decimal string, else nil is written.
+// An error occurs if the value has no finite decimal representation.
+func (o OptionalDecimal) Value() (driver.Value, error) {
+	if o.value == nil {
+		return nil, nil
+	}
+
+	str, ok := decimalString(o.value)
+	if !ok {
+		return nil, fmt.Errorf("%s has no finite decimal representation", str)
+	}
+
+	return str, nil
+}
+
+// String returns "Optional (decimal)" if present, else "Optional" if it is empty.
+// The decimal is the canonical decimal string if it is finite, else the fractional form,
+// which is passed as a string to any formatter set by SetValueFormatter.
+func (o OptionalDecimal) String() string {
+	if o.value == nil {
+		return emptyString
+	}
+
+	str, _ := decimalString(o.value)
+	return fmt.Sprintf("Optional (%s)", valueFormatter.Load().(func(interface{}) string)(str))
+}
@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"database/sql"
+	"encoding"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionalDecimalOfGet(t *testing.T) {
+	for _, opt := range []OptionalDecimal{OfDecimal(), OfDecimal(nil), {}} {
+		assert.True(t, opt.IsEmpty())
+		assert.False(t, opt.IsPresent())
+		val, valid := opt.Get()
+		assert.Nil(t, val)
+		assert.False(t, valid)
+
+		func() {
+			defer func() {
+				assert.True(t, errNotPresent == recover())
+			}()
+
+			opt.MustGet()
+			assert.Fail(t, "Expected Panic")
+		}()
+	}
+
+	// Construction and Get both copy
+	r := big.NewRat(3, 2)
+	opt := OfDecimal(r)
+	r.SetInt64(5)
+	val, valid := opt.Get()
+	assert.True(t, valid)
+	assert.Equal(t, 0, big.NewRat(3, 2).Cmp(val))
+	val.SetInt64(7)
+	assert.Equal(t, 0, big.NewRat(3, 2).Cmp(opt.MustGet()))
+
+	// Present zero is not empty
+	opt = OfDecimal(new(big.Rat))
+	assert.True(t, opt.IsPresent())
+}
+
+func TestOptionalDecimalZeroValueMatrix(t *testing.T) {
+	var (
+		zval OptionalDecimal
+		of   = OfDecimal()
+		one  = OfDecimal(big.NewRat(1, 1))
+	)
+
+	for name, method := range map[string]func(OptionalDecimal) interface{}{
+		"Get": func(o OptionalDecimal) interface{} {
+			val, valid := o.Get()
+			return []interface{}{val, valid}
+		},
+		"MustGet": func(o OptionalDecimal) interface{} {
+			defer func() { recover() }()
+			return o.MustGet()
+		},
+		"IsEmpty":     func(o OptionalDecimal) interface{} { return o.IsEmpty() },
+		"IsPresent":   func(o OptionalDecimal) interface{} { return o.IsPresent() },
+		"Equal":       func(o OptionalDecimal) interface{} { return []bool{o.Equal(of), o.Equal(one)} },
+		"Add":         func(o OptionalDecimal) interface{} { return o.Add(one) },
+		"AsOptional":  func(o OptionalDecimal) interface{} { return o.AsOptional() },
+		"Fingerprint": func(o OptionalDecimal) interface{} { return o.Fingerprint() },
+		"MarshalBinary": func(o OptionalDecimal) interface{} {
+			data, err := o.MarshalBinary()
+			return []interface{}{data, err}
+		},
+		"Value": func(o OptionalDecimal) interface{} {
+			val, err := o.Value()
+			return []interface{}{val, err}
+		},
+		"String": func(o OptionalDecimal) interface{} { return o.String() },
+	} {
+		assert.Equal(t, method(of), method(zval), name)
+	}
+}
+
+func TestOptionalDecimalPointerMethods(t *testing.T) {
+	// Only decoding methods may modify an OptionalDecimal
+	assert.Equal(t, []string{"Scan", "ScanDelta", "UnmarshalBinary"}, pointerMethods(reflect.TypeOf(OptionalDecimal{})))
+}
+
+func TestOptionalDecimalAsOptional(t *testing.T) {
+	assert.Equal(t, Of(), OfDecimal().AsOptional())
+
+	r := big.NewRat(3, 2)
+	opt := OfDecimal(r).AsOptional()
+	assert.Equal(t, 0, r.Cmp(opt.MustGet().(*big.Rat)))
+	assert.False(t, r == opt.MustGet().(*big.Rat))
+
+	// matching type
+	dec, ok := ToOptionalDecimal(opt)
+	assert.True(t, ok)
+	assert.True(t, OfDecimal(r).Equal(dec))
+	assert.False(t, opt.MustGet().(*big.Rat) == dec.value)
+
+	// mismatched type
+	dec, ok = ToOptionalDecimal(Of(1.5))
+	assert.False(t, ok)
+	assert.True(t, dec.IsEmpty())
+
+	// empty
+	dec, ok = ToOptionalDecimal(Of())
+	assert.True(t, ok)
+	assert.True(t, dec.IsEmpty())
+}
+
+func TestOptionalDecimalString(t *testing.T) {
+	opt, err := OfDecimalString("")
+	assert.Nil(t, err)
+	assert.True(t, opt.IsEmpty())
+
+	opt, err = OfDecimalString("12.50")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, big.NewRat(25, 2).Cmp(opt.MustGet()))
+
+	opt, err = OfDecimalString("0")
+	assert.Nil(t, err)
+	assert.True(t, opt.IsPresent())
+
+	opt, err = OfDecimalString("12.5x")
+	assert.Equal(t, fmt.Errorf("cannot parse %q as a decimal", "12.5x"), err)
+	assert.True(t, opt.IsEmpty())
+
+	assert.Equal(t, emptyString, OfDecimal().String())
+	assert.Equal(t, "Optional (12.5)", OfDecimal(big.NewRat(25, 2)).String())
+	assert.Equal(t, "Optional (1/3)", OfDecimal(big.NewRat(1, 3)).String())
+}
+
+func TestOptionalDecimalEqual(t *testing.T) {
+	a, _ := OfDecimalString("1.5")
+	b, _ := OfDecimalString("1.50")
+	c, _ := OfDecimalString("1.51")
+
+	assert.True(t, a.Equal(b))
+	assert.False(t, a.Equal(c))
+	assert.False(t, a.Equal(OfDecimal()))
+	assert.False(t, OfDecimal().Equal(a))
+	assert.True(t, OfDecimal().Equal(OfDecimal()))
+}
+
+func TestOptionalDecimalMaxMin(t *testing.T) {
+	a, _ := OfDecimalString("1.5")
+	b, _ := OfDecimalString("2.5")
+	empty := OfDecimal()
+
+	assert.True(t, a.Max(b).Equal(b))
+	assert.True(t, b.Max(a).Equal(b))
+	assert.True(t, a.Min(b).Equal(a))
+	assert.True(t, b.Min(a).Equal(a))
+
+	assert.True(t, a.Max(empty).Equal(a))
+	assert.True(t, empty.Max(a).Equal(a))
+	assert.True(t, a.Min(empty).Equal(a))
+	assert.True(t, empty.Min(a).Equal(a))
+
+	assert.True(t, empty.Max(empty).IsEmpty())
+	assert.True(t, empty.Min(empty).IsEmpty())
+}
+
+func TestOptionalDecimalBinary(t *testing.T) {
+	var (
+		_ encoding.BinaryMarshaler   = OptionalDecimal{}
+		_ encoding.BinaryUnmarshaler = &OptionalDecimal{}
+	)
+
+	data, err := OfDecimal().MarshalBinary()
+	assert.Equal(t, []byte{0}, data)
+	assert.Nil(t, err)
+
+	data, err = OfDecimal(big.NewRat(3, 2)).MarshalBinary()
+	assert.Equal(t, []byte{1, 3, '3', '/', '2'}, data)
+	assert.Nil(t, err)
+
+	// Round trips, including zero and values with no finite decimal representation
+	for _, opt := range []OptionalDecimal{OfDecimal(), OfDecimal(new(big.Rat)), OfDecimal(big.NewRat(-3, 2)), OfDecimal(big.NewRat(1, 3))} {
+		data, err = opt.MarshalBinary()
+		assert.Nil(t, err)
+
+		dec := OfDecimal(big.NewRat(7, 1))
+		assert.Nil(t, dec.UnmarshalBinary(data))
+		assert.True(t, opt.Equal(dec))
+	}
+
+	// Invalid data leaves the OptionalDecimal unmodified
+	dec := OfDecimal(big.NewRat(7, 1))
+	for _, data := range [][]byte{nil, {}, {2}, {1}, {0, 0}, {1, 2, '1'}, {1, 1, '1', '2'}, {1, 1, 'x'}} {
+		assert.Equal(t, fmt.Errorf("cannot unmarshal %d bytes as a binary decimal", len(data)), dec.UnmarshalBinary(data))
+		assert.True(t, OfDecimal(big.NewRat(7, 1)).Equal(dec))
+	}
+}
+
+func TestOptionalDecimalFingerprint(t *testing.T) {
+	a, _ := OfDecimalString("1.5")
+	b, _ := OfDecimalString("1.50")
+	c, _ := OfDecimalString("1.51")
+
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	assert.False(t, a.Fingerprint() == c.Fingerprint())
+	assert.Equal(t, OfDecimal().Fingerprint(), OptionalDecimal{}.Fingerprint())
+	assert.False(t, uint64(0) == OfDecimal().Fingerprint())
+	assert.False(t, OfDecimal(new(big.Rat)).Fingerprint() == OfDecimal().Fingerprint())
+}
+
+func TestOptionalDecimalArithmetic(t *testing.T) {
+	a, _ := OfDecimalString("0.1")
+	b, _ := OfDecimalString("0.2")
+	c, _ := OfDecimalString("0.3")
+	d, _ := OfDecimalString("0.02")
+
+	assert.True(t, a.Add(b).Equal(c))
+	assert.True(t, c.Sub(b).Equal(a))
+	assert.True(t, a.Mul(b).Equal(d))
+
+	assert.True(t, a.Add(OfDecimal()).IsEmpty())
+	assert.True(t, OfDecimal().Sub(a).IsEmpty())
+	assert.True(t, OfDecimal().Mul(OfDecimal()).IsEmpty())
+
+	// Operands are not modified
+	assert.Equal(t, "Optional (0.1)", a.String())
+	assert.Equal(t, "Optional (0.2)", b.String())
+}
+
+func TestOptionalDecimalScanValue(t *testing.T) {
+	var opt OptionalDecimal
+	assert.Nil(t, opt.Scan("1.25"))
+	assert.Equal(t, 0, big.NewRat(5, 4).Cmp(opt.MustGet()))
+
+	assert.Nil(t, opt.Scan([]byte("-3.5")))
+	assert.Equal(t, 0, big.NewRat(-7, 2).Cmp(opt.MustGet()))
+
+	assert.Nil(t, opt.Scan(int64(4)))
+	assert.Equal(t, 0, big.NewRat(4, 1).Cmp(opt.MustGet()))
+
+	assert.Nil(t, opt.Scan(nil))
+	assert.True(t, opt.IsEmpty())
+
+	assert.Nil(t, opt.Scan("2"))
+	assert.Equal(t, fmt.Errorf("cannot parse %q as a decimal", "x"), opt.Scan("x"))
+	assert.Equal(t, fmt.Errorf("cannot scan a value of type %T", true), opt.Scan(true))
+	assert.Equal(t, fmt.Errorf("cannot scan %v as a decimal", math.NaN()), opt.Scan(math.NaN()))
+	assert.Equal(t, fmt.Errorf("cannot scan %v as a decimal", math.Inf(1)), opt.Scan(math.Inf(1)))
+	assert.Equal(t, fmt.Errorf("cannot scan %v as a decimal", math.Inf(-1)), opt.Scan(math.Inf(-1)))
+	assert.Equal(t, 0, big.NewRat(2, 1).Cmp(opt.MustGet()))
+
+	// Floats are stored as their shortest decimal form
+	assert.Nil(t, opt.Scan(1.1))
+	assert.Equal(t, 0, big.NewRat(11, 10).Cmp(opt.MustGet()))
+	assert.Nil(t, opt.Scan(-2.5e-3))
+	assert.Equal(t, 0, big.NewRat(-1, 400).Cmp(opt.MustGet()))
+	assert.Nil(t, opt.Scan(1e21))
+	assert.Equal(t, "Optional (1000000000000000000000)", opt.String())
+
+	sc := (sql.Scanner)(&opt)
+	assert.NotNil(t, &sc)
+
+	val, err := OfDecimal().Value()
+	assert.Nil(t, val)
+	assert.Nil(t, err)
+
+	for str, rat := range map[string]*big.Rat{
+		"0":       new(big.Rat),
+		"12":      big.NewRat(12, 1),
+		"-0.5":    big.NewRat(-1, 2),
+		"0.04":    big.NewRat(1, 25),
+		"123.125": big.NewRat(985, 8),
+	} {
+		val, err = OfDecimal(rat).Value()
+		assert.Equal(t, str, val)
+		assert.Nil(t, err)
+	}
+
+	val, err = OfDecimal(big.NewRat(1, 3)).Value()
+	assert.Nil(t, val)
+	assert.Equal(t, fmt.Errorf("1/3 has no finite decimal representation"), err)
+}
+
+func TestOptionalDecimalScanDelta(t *testing.T) {
+	var opt OptionalDecimal
+	for _, step := range []struct {
+		src     interface{}
+		changed bool
+		err     bool
+	}{
+		{nil, false, false},
+		{"1", true, false},
+		{"2", false, false},
+		{"x", false, true},
+		{nil, true, false},
+		{true, false, true},
+		{int64(0), true, false},
+	} {
+		changed, err := opt.ScanDelta(step.src)
+		assert.Equal(t, step.changed, changed)
+		assert.Equal(t, step.err, err != nil)
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"github.com/bantling/gofuncs"
+)
+
+// FilterChain is an immutable chain of Filter and FilterNamed calls on an Optional,
+// which records the name of the first named filter that dropped the value, for diagnosing validation pipelines.
+// The reason is kept here rather than in the Optional, so that an Optional emptied by a filter is equal to any other empty Optional.
+// The zero value is ready to use, and is a chain of an empty Optional.
+type FilterChain struct {
+	opt    Optional
+	reason string
+}
+
+// FilterChain returns a FilterChain that begins with this Optional
+func (o Optional) FilterChain() FilterChain {
+	return FilterChain{opt: o}
+}
+
+// Filter is the same as Optional.Filter, and does not record a reason if the predicate drops the value.
+// If the value was already dropped, the chain is returned unchanged.
+func (c FilterChain) Filter(predicate interface{}) FilterChain {
+	if !c.opt.present {
+		return c
+	}
+
+	return FilterChain{opt: c.opt.Filter(predicate)}
+}
+
+// FilterNamed is the same as Filter, except that if the predicate drops the value, the given name is recorded as the reason.
+// If the value was already dropped, the chain is returned unchanged, so the reason is always the first named filter that failed.
+func (c FilterChain) FilterNamed(name string, predicate interface{}) FilterChain {
+	if !c.opt.present {
+		return c
+	}
+
+	return gofuncs.Ternary(gofuncs.Filter(predicate)(c.opt.value), c, FilterChain{reason: name}).(FilterChain)
+}
+
+// Result returns the filtered Optional, and the name given to the FilterNamed call that dropped the value.
+// The name is an empty string if the Optional is present, was never present, or was dropped by an unnamed Filter.
+func (c FilterChain) Result() (Optional, string) {
+	return c.opt, c.reason
+}
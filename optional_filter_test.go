@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterChain(t *testing.T) {
+	var (
+		positive = func(val int) bool { return val > 0 }
+		even     = func(val int) bool { return val%2 == 0 }
+		small    = func(val int) bool { return val < 10 }
+	)
+
+	opt, reason := Of(4).FilterChain().FilterNamed("positive", positive).FilterNamed("even", even).FilterNamed("small", small).Result()
+	assert.Equal(t, Of(4), opt)
+	assert.Equal(t, "", reason)
+
+	opt, reason = Of(3).FilterChain().FilterNamed("positive", positive).FilterNamed("even", even).FilterNamed("small", small).Result()
+	assert.Equal(t, Of(), opt)
+	assert.True(t, Optional{} == opt)
+	assert.Equal(t, "even", reason)
+
+	opt, reason = Of(-12).FilterChain().FilterNamed("positive", positive).Filter(even).FilterNamed("small", small).Result()
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, "positive", reason)
+
+	// Unnamed filters do not record a reason
+	opt, reason = Of(3).FilterChain().Filter(even).FilterNamed("small", small).Result()
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, "", reason)
+
+	// Never present
+	opt, reason = Of().FilterChain().FilterNamed("positive", positive).Result()
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, "", reason)
+
+	// The zero value is a chain of an empty Optional
+	opt, reason = FilterChain{}.FilterNamed("positive", positive).Result()
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, "", reason)
+
+	// Redaction is preserved
+	opt, _ = Of(4).Redacted().FilterChain().FilterNamed("even", even).Result()
+	assert.True(t, opt.IsRedacted())
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"sync"
+)
+
+// LazyOptional is an Optional whose value is provided by a supplier that is not called until the first time it is accessed,
+// which is useful when the value is expensive to construct and may not be needed.
+// The supplier is called at most once, and its result is memoized for all later accesses.
+// It is safe for concurrent use: if many goroutines access it at once, one calls the supplier while the others wait for the result.
+// The zero value has no supplier, and holds an empty Optional.
+// A LazyOptional must not be copied after first use.
+type LazyOptional struct {
+	once     sync.Once
+	supplier func() (interface{}, bool)
+	opt      Optional
+}
+
+// OfSupplier returns a LazyOptional that calls the given supplier on first access.
+// If the supplier returns true, the value is wrapped as by Of, so a nil value is still empty, else the result is empty.
+func OfSupplier(supplier func() (interface{}, bool)) *LazyOptional {
+	return &LazyOptional{supplier: supplier}
+}
+
+// Get calls the supplier if it has not been called yet, and returns the memoized Optional.
+// If the supplier panics, the panic propagates to the caller that called it, and the result is empty for all later accesses.
+func (l *LazyOptional) Get() Optional {
+	l.once.Do(func() {
+		if l.supplier == nil {
+			return
+		}
+
+		if value, present := l.supplier(); present {
+			l.opt = Of(value)
+		}
+
+		// Release the supplier and anything it references
+		l.supplier = nil
+	})
+
+	return l.opt
+}
+
+// IsEmpty returns true if the Optional returned by Get is not present
+func (l *LazyOptional) IsEmpty() bool {
+	return l.Get().IsEmpty()
+}
+
+// IsPresent returns true if the Optional returned by Get is present
+func (l *LazyOptional) IsPresent() bool {
+	return l.Get().IsPresent()
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyOptional(t *testing.T) {
+	calls := 0
+	l := OfSupplier(func() (interface{}, bool) {
+		calls++
+		return "a", true
+	})
+
+	// Not called until accessed
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, Of("a"), l.Get())
+	assert.Equal(t, 1, calls)
+
+	// Called at most once
+	assert.True(t, l.IsPresent())
+	assert.False(t, l.IsEmpty())
+	assert.Equal(t, Of("a"), l.Get())
+	assert.Equal(t, 1, calls)
+
+	// Not present
+	calls = 0
+	l = OfSupplier(func() (interface{}, bool) {
+		calls++
+		return "a", false
+	})
+	assert.True(t, l.IsEmpty())
+	assert.False(t, l.IsPresent())
+	assert.Equal(t, Of(), l.Get())
+	assert.Equal(t, 1, calls)
+
+	// A nil value is empty, as for Of
+	assert.Equal(t, Of(), OfSupplier(func() (interface{}, bool) { return nil, true }).Get())
+
+	// The zero value is empty
+	var zero LazyOptional
+	assert.Equal(t, Of(), zero.Get())
+	assert.Equal(t, Of(), OfSupplier(nil).Get())
+}
+
+func TestLazyOptionalPanic(t *testing.T) {
+	l := OfSupplier(func() (interface{}, bool) { panic("failed") })
+
+	func() {
+		defer func() {
+			assert.Equal(t, "failed", recover())
+		}()
+
+		l.Get()
+		assert.Fail(t, "Expected Panic")
+	}()
+
+	// Not called again
+	assert.Equal(t, Of(), l.Get())
+}
+
+func TestLazyOptionalConcurrent(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls = 0
+		wg    sync.WaitGroup
+		l     = OfSupplier(func() (interface{}, bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			return 1, true
+		})
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, Of(1), l.Get())
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 1, calls)
+}
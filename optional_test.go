@@ -3,9 +3,22 @@
 package gooptional
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bantling/goiter"
 	"github.com/stretchr/testify/assert"
@@ -102,6 +115,74 @@ func TestOptionalOfEmptyPresentGet(t *testing.T) {
 	}()
 }
 
+func TestOptionalZeroValueMatrix(t *testing.T) {
+	var (
+		zval Optional
+		of   = Of()
+		inc  = func(val int) int { return val + 1 }
+		yes  = func(interface{}) bool { return true }
+	)
+
+	for name, method := range map[string]func(Optional) interface{}{
+		"Get": func(o Optional) interface{} {
+			val, valid := o.Get()
+			return []interface{}{val, valid}
+		},
+		"MustGet": func(o Optional) interface{} {
+			defer func() { recover() }()
+			return o.MustGet()
+		},
+		"OrElse":       func(o Optional) interface{} { return o.OrElse(1) },
+		"IsEmpty":      func(o Optional) interface{} { return o.IsEmpty() },
+		"IsPresent":    func(o Optional) interface{} { return o.IsPresent() },
+		"EqualNumeric": func(o Optional) interface{} { return o.EqualNumeric(Of()) },
+		"Changed":      func(o Optional) interface{} { return Changed(o, Of()) },
+		"Map":          func(o Optional) interface{} { return o.Map(inc) },
+		"MapFunc":      func(o Optional) interface{} { return o.MapFunc(func(v interface{}) interface{} { return v }) },
+		"FlatMap":      func(o Optional) interface{} { return o.FlatMap(func(v interface{}) Optional { return Of(v) }) },
+		"Filter":       func(o Optional) interface{} { return o.Filter(yes) },
+		"Iter":         func(o Optional) interface{} { return o.Iter().Next() },
+		"Value": func(o Optional) interface{} {
+			val, err := o.Value()
+			return []interface{}{val, err}
+		},
+		"String":      func(o Optional) interface{} { return o.String() },
+		"GoString":    func(o Optional) interface{} { return o.GoString() },
+		"ValueString": func(o Optional) interface{} { return o.ValueString() },
+	} {
+		assert.Equal(t, method(of), method(zval), name)
+	}
+
+	assert.True(t, zval == of)
+}
+
+// pointerMethods returns the sorted names of the methods of the given type that have a pointer receiver
+func pointerMethods(typ reflect.Type) []string {
+	var names []string
+	ptrType := reflect.PtrTo(typ)
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		if name := ptrType.Method(i).Name; !hasMethod(typ, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// hasMethod returns true if the given type has a method of the given name in its method set
+func hasMethod(typ reflect.Type, name string) bool {
+	_, haveIt := typ.MethodByName(name)
+	return haveIt
+}
+
+func TestOptionalPointerMethods(t *testing.T) {
+	// Only decoding methods may modify an Optional
+	assert.Equal(t,
+		[]string{"GobDecode", "OnlyChangedScan", "Scan", "ScanDelta", "ScanStrict", "ScanText"},
+		pointerMethods(reflect.TypeOf(Optional{})),
+	)
+}
+
 func TestOptionalFilter(t *testing.T) {
 	opt := Of(1)
 	assert.True(t, opt == opt.Filter(func(val interface{}) bool { return true }))
@@ -111,6 +192,387 @@ func TestOptionalFilter(t *testing.T) {
 	assert.True(t, Of().Filter(func(interface{}) bool { return true }).IsEmpty())
 }
 
+func TestOptionalOfFlattens(t *testing.T) {
+	assert.Equal(t, Of(1), Of(Of(1)))
+	assert.Equal(t, Of(), Of(Of()))
+
+	redacted := Of("secret").Redacted()
+	assert.Equal(t, redacted, Of(redacted))
+
+	val, present := Of(OfDecimal(big.NewRat(3, 2))).Get()
+	assert.True(t, present)
+	assert.Equal(t, 0, big.NewRat(3, 2).Cmp(val.(*big.Rat)))
+	assert.Equal(t, Of(), Of(OfDecimal()))
+
+	assert.Equal(t, Of(optionalUUIDBytes), Of(OfUUID(optionalUUIDBytes)))
+	assert.Equal(t, Of(), Of(OfUUID()))
+
+	// Flattening loses the typed Value and String behaviour
+	dec := OfDecimal(big.NewRat(3, 2))
+	val, err := dec.Value()
+	assert.Equal(t, "1.5", val)
+	assert.Nil(t, err)
+	val, err = Of(dec).Value()
+	assert.Equal(t, 0, big.NewRat(3, 2).Cmp(val.(*big.Rat)))
+	assert.Nil(t, err)
+	assert.Equal(t, "Optional (1.5)", dec.String())
+	assert.Equal(t, "Optional (3/2)", Of(dec).String())
+
+	uuid := OfUUID(optionalUUIDBytes)
+	val, err = uuid.Value()
+	assert.Equal(t, optionalUUIDString, val)
+	assert.Nil(t, err)
+	val, err = Of(uuid).Value()
+	assert.Equal(t, optionalUUIDBytes, val)
+	assert.Nil(t, err)
+	assert.Equal(t, "Optional ("+optionalUUIDString+")", uuid.String())
+	assert.Equal(t, fmt.Sprintf("Optional (%v)", optionalUUIDBytes), Of(uuid).String())
+
+	// Converting back restores it
+	dec, _ = ToOptionalDecimal(Of(dec))
+	assert.Equal(t, "Optional (1.5)", dec.String())
+	uuid, _ = ToOptionalUUID(Of(uuid))
+	assert.Equal(t, "Optional ("+optionalUUIDString+")", uuid.String())
+
+	// Plain values are wrapped
+	assert.Equal(t, "a", Of("a").MustGet())
+}
+
+func TestOptionalOfAll(t *testing.T) {
+	assert.Equal(t, []Optional{}, OfAll())
+	assert.Equal(t, []Optional{Of("a"), Of(""), Of()}, OfAll("a", "", nil))
+
+	opts := OfAll(1, 0)
+	assert.Equal(t, 2, len(opts))
+	assert.True(t, AllPresent(opts[0], opts[1]))
+}
+
+func TestOptionalFromResult(t *testing.T) {
+	assert.Equal(t, Of(12), FromResult(strconv.Atoi("12")))
+	assert.True(t, FromResult(strconv.Atoi("x")).IsEmpty())
+	assert.True(t, FromResult(1, fmt.Errorf("failed")).IsEmpty())
+	assert.True(t, FromResult(nil, nil).IsEmpty())
+	assert.Equal(t, Of(0), FromResult(0, nil))
+}
+
+func TestOptionalReconstruct(t *testing.T) {
+	for _, opt := range []Optional{Of(), Of(0), Of(""), Of(1)} {
+		assert.Equal(t, opt, Reconstruct(opt.Get()))
+	}
+
+	assert.Equal(t, Of(), Reconstruct(1, false))
+	assert.Equal(t, Of(), Reconstruct(nil, true))
+}
+
+func TestOptionalOfRecover(t *testing.T) {
+	assert.Equal(t, Of(1), OfRecover(func() interface{} { return 1 }))
+	assert.True(t, OfRecover(func() interface{} { return nil }).IsEmpty())
+
+	var val interface{} = "a"
+	assert.True(t, OfRecover(func() interface{} { return val.(int) }).IsEmpty())
+	assert.True(t, OfRecover(func() interface{} { panic("fail") }).IsEmpty())
+}
+
+func TestOptionalLookupEnvOptional(t *testing.T) {
+	const key = "GOOPTIONAL_TEST_LOOKUP_ENV"
+	defer os.Unsetenv(key)
+
+	os.Unsetenv(key)
+	assert.True(t, LookupEnvOptional(key).IsEmpty())
+
+	os.Setenv(key, "")
+	assert.Equal(t, "", LookupEnvOptional(key).MustGet())
+
+	os.Setenv(key, "value")
+	assert.Equal(t, "value", LookupEnvOptional(key).MustGet())
+}
+
+func TestOptionalLookupEnvParsed(t *testing.T) {
+	const key = "GOOPTIONAL_TEST_LOOKUP_ENV_PARSED"
+	defer os.Unsetenv(key)
+
+	for _, lookup := range []func(string) (Optional, error){LookupEnvInt, LookupEnvFloat, LookupEnvBool} {
+		os.Unsetenv(key)
+		opt, err := lookup(key)
+		assert.True(t, opt.IsEmpty())
+		assert.Nil(t, err)
+	}
+
+	for val, cases := range map[string][]struct {
+		lookup func(string) (Optional, error)
+		kind   string
+		result interface{}
+		cause  error
+	}{
+		"1":                    {{LookupEnvInt, "int", 1, nil}, {LookupEnvFloat, "float", 1.0, nil}, {LookupEnvBool, "bool", true, nil}},
+		"1.5":                  {{LookupEnvInt, "int", nil, strconv.ErrSyntax}, {LookupEnvFloat, "float", 1.5, nil}, {LookupEnvBool, "bool", nil, strconv.ErrSyntax}},
+		"x":                    {{LookupEnvInt, "int", nil, strconv.ErrSyntax}, {LookupEnvFloat, "float", nil, strconv.ErrSyntax}, {LookupEnvBool, "bool", nil, strconv.ErrSyntax}},
+		"":                     {{LookupEnvInt, "int", nil, strconv.ErrSyntax}, {LookupEnvFloat, "float", nil, strconv.ErrSyntax}, {LookupEnvBool, "bool", nil, strconv.ErrSyntax}},
+		"99999999999999999999": {{LookupEnvInt, "int", nil, strconv.ErrRange}},
+		"1e400":                {{LookupEnvFloat, "float", nil, strconv.ErrRange}},
+	} {
+		os.Setenv(key, val)
+		for _, c := range cases {
+			opt, err := c.lookup(key)
+			if c.result == nil {
+				assert.True(t, opt.IsEmpty())
+				assert.True(t, strings.HasPrefix(err.Error(), fmt.Sprintf("environment variable %s value %q is not a valid %s: ", key, val, c.kind)))
+				assert.True(t, errors.Is(err, c.cause), val)
+
+				var numErr *strconv.NumError
+				assert.True(t, errors.As(err, &numErr))
+				assert.Equal(t, val, numErr.Num)
+			} else {
+				assert.Equal(t, Of(c.result), opt)
+				assert.Nil(t, err)
+			}
+		}
+	}
+}
+
+func TestOptionalFromMap(t *testing.T) {
+	strs := map[string]string{"a": "1", "empty": ""}
+	assert.Equal(t, Of("1"), FromMap(strs, "a"))
+	assert.Equal(t, Of(""), FromMap(strs, "empty"))
+	assert.Equal(t, Of(), FromMap(strs, "missing"))
+
+	ints := map[string]int{"zero": 0}
+	assert.Equal(t, Of(0), FromMap(ints, "zero"))
+	assert.Equal(t, Of(), FromMap(ints, "missing"))
+
+	floats := map[int]float64{1: 1.5}
+	assert.Equal(t, Of(1.5), FromMap(floats, 1))
+	assert.Equal(t, Of(), FromMap(floats, 2))
+
+	assert.Equal(t, Of(), FromMap(map[string]interface{}{"nil": nil}, "nil"))
+	assert.Equal(t, Of("nil key"), FromMap(map[interface{}]string{nil: "nil key"}, nil))
+	assert.Equal(t, Of(), FromMap(map[string]int(nil), "a"))
+
+	func() {
+		defer func() {
+			assert.Equal(t, "FromMap requires a map, not []int", recover())
+		}()
+
+		FromMap([]int{1}, 0)
+		assert.Fail(t, "Expected Panic")
+	}()
+
+	func() {
+		defer func() {
+			assert.Equal(t, "FromMap requires a key of type string, not int", recover())
+		}()
+
+		FromMap(strs, 1)
+		assert.Fail(t, "Expected Panic")
+	}()
+}
+
+func TestOptionalFirstPresent(t *testing.T) {
+	var (
+		block   = make(chan struct{})
+		empty   = func() Optional { return Of() }
+		present = func() Optional { return Of(1) }
+		slow    = func() Optional { <-block; return Of(2) }
+	)
+	defer close(block)
+
+	assert.Equal(t, Of(1), FirstPresent(time.Minute, empty, slow, present, empty))
+	assert.Equal(t, Of(), FirstPresent(time.Minute, empty, empty))
+	assert.Equal(t, Of(), FirstPresent(time.Minute))
+	assert.Equal(t, Of(), FirstPresent(10*time.Millisecond, empty, slow))
+}
+
+func TestOptionalOfRegexpMatch(t *testing.T) {
+	// no groups
+	re := regexp.MustCompile(`\d+`)
+	assert.Equal(t, Of("123"), OfRegexpMatch(re, "abc123def456"))
+	assert.Equal(t, Of(), OfRegexpMatch(re, "abc"))
+
+	// first group
+	re = regexp.MustCompile(`id=(\w*)`)
+	assert.Equal(t, Of("42"), OfRegexpMatch(re, "name=x&id=42"))
+	assert.Equal(t, Of(""), OfRegexpMatch(re, "id="))
+	assert.Equal(t, Of(), OfRegexpMatch(re, "name=x"))
+
+	// first group does not participate
+	assert.Equal(t, Of(), OfRegexpMatch(regexp.MustCompile(`(a)?b`), "b"))
+
+	// named group
+	re = regexp.MustCompile(`(?P<year>\d{4})-(?P<month>\d{2})`)
+	assert.Equal(t, Of("2020"), OfRegexpNamedMatch(re, "on 2020-05", "year"))
+	assert.Equal(t, Of("05"), OfRegexpNamedMatch(re, "on 2020-05", "month"))
+	assert.Equal(t, Of(), OfRegexpNamedMatch(re, "on 2020", "year"))
+	assert.Equal(t, Of(), OfRegexpNamedMatch(re, "on 2020-05", "day"))
+	assert.Equal(t, Of(), OfRegexpNamedMatch(re, "on 2020-05", ""))
+}
+
+func TestOptionalFirstPresentByKeys(t *testing.T) {
+	m := map[string]Optional{
+		"override": Of(),
+		"custom":   Of(1),
+		"default":  Of(2),
+	}
+
+	assert.Equal(t, Of(1), FirstPresentByKeys(m, "override", "custom", "default"))
+	assert.Equal(t, Of(2), FirstPresentByKeys(m, "default", "custom"))
+	assert.Equal(t, Of(2), FirstPresentByKeys(m, "missing", "override", "default"))
+	assert.True(t, FirstPresentByKeys(m, "missing", "override").IsEmpty())
+	assert.True(t, FirstPresentByKeys(m).IsEmpty())
+	assert.True(t, FirstPresentByKeys(nil, "custom").IsEmpty())
+}
+
+func TestOptionalIsPresentAndIsEmptyOr(t *testing.T) {
+	var (
+		called   = false
+		positive = func(val int) bool {
+			called = true
+			return val > 0
+		}
+	)
+
+	assert.True(t, Of(1).IsPresentAnd(positive))
+	assert.False(t, Of(-1).IsPresentAnd(positive))
+	assert.True(t, Of(1).IsEmptyOr(positive))
+	assert.False(t, Of(-1).IsEmptyOr(positive))
+
+	called = false
+	assert.False(t, Of().IsPresentAnd(positive))
+	assert.True(t, Of().IsEmptyOr(positive))
+	assert.False(t, called)
+}
+
+func TestOptionalRequireAll(t *testing.T) {
+	assert.Nil(t, RequireAll())
+	assert.Nil(t, RequireAll(Of(1), Of(""), OfDecimal(big.NewRat(1, 2))))
+	assert.Equal(t, fmt.Errorf("optionals at indexes [1] are not present"), RequireAll(Of(1), Of(), Of(2)))
+	assert.Equal(t, fmt.Errorf("optionals at indexes [0, 2, 3] are not present"), RequireAll(Of(), Of(1), OfDecimal(), Of()))
+}
+
+// optionalCounter counts how many times IsPresent is called
+type optionalCounter struct {
+	present bool
+	calls   *int
+}
+
+func (c optionalCounter) IsPresent() bool {
+	*c.calls++
+	return c.present
+}
+
+func TestOptionalAllAnyPresent(t *testing.T) {
+	assert.True(t, AllPresent())
+	assert.False(t, AnyPresent())
+
+	// all present
+	assert.True(t, AllPresent(Of(1), Of(""), OfDecimal(big.NewRat(1, 2))))
+	assert.True(t, AnyPresent(Of(1), Of(""), OfDecimal(big.NewRat(1, 2))))
+
+	// none present
+	assert.False(t, AllPresent(Of(), OfDecimal()))
+	assert.False(t, AnyPresent(Of(), OfDecimal()))
+
+	// mixed
+	assert.False(t, AllPresent(Of(1), Of(), Of(2)))
+	assert.True(t, AnyPresent(Of(), Of(1), Of()))
+
+	// short circuit
+	calls := 0
+	assert.False(t, AllPresent(optionalCounter{false, &calls}, optionalCounter{true, &calls}))
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	assert.True(t, AnyPresent(optionalCounter{true, &calls}, optionalCounter{false, &calls}))
+	assert.Equal(t, 1, calls)
+}
+
+func TestOptionalEqualNumeric(t *testing.T) {
+	assert.True(t, Of().EqualNumeric(Of()))
+	assert.False(t, Of().EqualNumeric(Of(0)))
+	assert.False(t, Of(0).EqualNumeric(Of()))
+
+	// int and int64
+	assert.True(t, Of(5).EqualNumeric(Of(int64(5))))
+	assert.True(t, Of(int8(-5)).EqualNumeric(Of(-5)))
+	assert.False(t, Of(5).EqualNumeric(Of(int64(6))))
+	assert.True(t, Of(uint64(math.MaxUint64)).EqualNumeric(Of(uint64(math.MaxUint64))))
+	assert.False(t, Of(-1).EqualNumeric(Of(uint64(math.MaxUint64))))
+
+	// float and int
+	assert.True(t, Of(5).EqualNumeric(Of(5.0)))
+	assert.True(t, Of(float32(0.5)).EqualNumeric(Of(0.5)))
+	assert.False(t, Of(5).EqualNumeric(Of(5.5)))
+	assert.False(t, Of(math.NaN()).EqualNumeric(Of(math.NaN())))
+
+	// Comparison is exact for large values
+	assert.True(t, Of(int64(1<<53)).EqualNumeric(Of(float64(1<<53))))
+	assert.False(t, Of(int64(1<<53+1)).EqualNumeric(Of(float64(1<<53))))
+
+	// non-numeric
+	assert.True(t, Of("5").EqualNumeric(Of("5")))
+	assert.False(t, Of("5").EqualNumeric(Of(5)))
+	assert.True(t, Of([]int{1}).EqualNumeric(Of([]int{1})))
+	assert.False(t, Of([]int{1}).EqualNumeric(Of([]int{2})))
+}
+
+func TestOptionalDiffChanged(t *testing.T) {
+	// equal
+	for _, opt := range []Optional{Of(), Of(1), Of([]int{1})} {
+		assert.False(t, Changed(opt, opt))
+		assert.True(t, Diff(opt, opt).IsEmpty())
+	}
+
+	// differing present values
+	assert.True(t, Changed(Of(1), Of(2)))
+	assert.Equal(t, Of(2), Diff(Of(1), Of(2)))
+	assert.True(t, Changed(Of(1), Of(int64(1))))
+
+	// presence flips
+	assert.True(t, Changed(Of(), Of(1)))
+	assert.Equal(t, Of(1), Diff(Of(), Of(1)))
+	assert.True(t, Changed(Of(1), Of()))
+	assert.True(t, Diff(Of(1), Of()).IsEmpty())
+}
+
+func TestOptionalForEach(t *testing.T) {
+	calls := 0
+	Of().ForEach(func(int) { calls++ })
+	assert.Equal(t, 0, calls)
+
+	Of(1).ForEach(func(i int) { calls += i })
+	assert.Equal(t, 1, calls)
+
+	Of(2).ForEach(func(v interface{}) { calls += v.(int) })
+	assert.Equal(t, 3, calls)
+}
+
+func TestOptionalAlso(t *testing.T) {
+	var (
+		present = 0
+		empty   = false
+		opt     = Of(1)
+	)
+	assert.True(t, opt == opt.Also(func(i int) { present = i }, func() { empty = true }))
+	assert.Equal(t, 1, present)
+	assert.False(t, empty)
+
+	present = 0
+	opt = Of()
+	assert.True(t, opt == opt.Also(func(i int) { present = i }, func() { empty = true }))
+	assert.Equal(t, 0, present)
+	assert.True(t, empty)
+}
+
+func TestOptionalPeekEmpty(t *testing.T) {
+	called := false
+	opt := Of(1)
+	assert.True(t, opt == opt.PeekEmpty(func() { called = true }))
+	assert.False(t, called)
+
+	opt = Of()
+	assert.True(t, opt == opt.PeekEmpty(func() { called = true }))
+	assert.True(t, called)
+}
+
 func TestOptionalIter(t *testing.T) {
 	var (
 		opt      Optional        = Of(1)
@@ -127,6 +589,51 @@ func TestOptionalIter(t *testing.T) {
 	assert.False(t, iter.Next())
 }
 
+func TestOptionalChan(t *testing.T) {
+	var vals []interface{}
+	for val := range Of(1).Chan() {
+		vals = append(vals, val)
+	}
+	assert.Equal(t, []interface{}{1}, vals)
+
+	vals = nil
+	for val := range Of().Chan() {
+		vals = append(vals, val)
+	}
+	assert.Nil(t, vals)
+
+	select {
+	case val, ok := <-Of("a").Chan():
+		assert.Equal(t, "a", val)
+		assert.True(t, ok)
+	default:
+		assert.Fail(t, "Expected a value")
+	}
+}
+
+func TestOptionalIterTyped(t *testing.T) {
+	iter := Of(1).IterInt()
+	assert.True(t, iter.Next())
+	assert.Equal(t, 1, iter.Value())
+	assert.False(t, iter.Next())
+	assert.False(t, Of(int64(1)).IterInt().Next())
+	assert.False(t, Of().IterInt().Next())
+
+	iter = Of(1.5).IterFloat()
+	assert.True(t, iter.Next())
+	assert.Equal(t, 1.5, iter.Value())
+	assert.False(t, iter.Next())
+	assert.False(t, Of(float32(1.5)).IterFloat().Next())
+	assert.False(t, Of().IterFloat().Next())
+
+	iter = Of("a").IterString()
+	assert.True(t, iter.Next())
+	assert.Equal(t, "a", iter.Value())
+	assert.False(t, iter.Next())
+	assert.False(t, Of(1).IterString().Next())
+	assert.False(t, Of().IterString().Next())
+}
+
 func TestOptionalMap(t *testing.T) {
 	too := func(val interface{}) interface{} {
 		return val.(int) + 1
@@ -134,22 +641,413 @@ func TestOptionalMap(t *testing.T) {
 	assert.True(t, Of().Map(too).IsEmpty())
 	assert.Equal(t, 2, Of(1).Map(too).MustGet())
 
-	tooint := func(val int) int {
-		return val + 1
-	}
-	assert.True(t, Of().Map(tooint).IsEmpty())
-	assert.Equal(t, 2, Of(1).Map(tooint).MustGet())
+	tooint := func(val int) int {
+		return val + 1
+	}
+	assert.True(t, Of().Map(tooint).IsEmpty())
+	assert.Equal(t, 2, Of(1).Map(tooint).MustGet())
+
+	tonp := func(val interface{}) interface{} {
+		return nil
+	}
+	assert.True(t, Of(1).Map(tonp).IsEmpty())
+
+	toz := func(val interface{}) interface{} {
+		return 0
+	}
+	assert.False(t, Of(1).Map(toz).IsEmpty())
+	assert.False(t, Of(1).Map(toz, ZeroValueIsPresent).IsEmpty())
+	assert.True(t, Of(1).Map(toz, ZeroValueIsEmpty).IsEmpty())
+}
+
+func TestOptionalMapFunc(t *testing.T) {
+	too := func(val interface{}) interface{} {
+		return val.(int) + 1
+	}
+	assert.True(t, Of().MapFunc(too).IsEmpty())
+	assert.Equal(t, 2, Of(1).MapFunc(too).MustGet())
+
+	tonp := func(val interface{}) interface{} {
+		return nil
+	}
+	assert.True(t, Of(1).MapFunc(tonp).IsEmpty())
+
+	var np *int
+	tonilp := func(val interface{}) interface{} {
+		return np
+	}
+	assert.True(t, Of(1).MapFunc(tonilp).IsEmpty())
+
+	toz := func(val interface{}) interface{} {
+		return 0
+	}
+	assert.False(t, Of(1).MapFunc(toz).IsEmpty())
+	assert.False(t, Of(1).MapFunc(toz, ZeroValueIsPresent).IsEmpty())
+	assert.True(t, Of(1).MapFunc(toz, ZeroValueIsEmpty).IsEmpty())
+}
+
+func BenchmarkOptionalMap(b *testing.B) {
+	var (
+		opt = Of(1)
+		too = func(val interface{}) interface{} { return val.(int) + 1 }
+	)
+
+	for i := 0; i < b.N; i++ {
+		opt.Map(too)
+	}
+}
+
+func BenchmarkOptionalMapFunc(b *testing.B) {
+	var (
+		opt = Of(1)
+		too = func(val interface{}) interface{} { return val.(int) + 1 }
+	)
+
+	for i := 0; i < b.N; i++ {
+		opt.MapFunc(too)
+	}
+}
+
+func TestOptionalMapWhile(t *testing.T) {
+	var (
+		called = false
+		double = func(val interface{}) (interface{}, bool) {
+			called = true
+			v := val.(int) * 2
+			return v, v < 10
+		}
+	)
+
+	assert.Equal(t, Of(8), Of(1).MapWhile(double).MapWhile(double).MapWhile(double))
+	assert.True(t, Of(1).MapWhile(double).MapWhile(double).MapWhile(double).MapWhile(double).IsEmpty())
+	assert.True(t, Of(5).MapWhile(double).IsEmpty())
+
+	called = false
+	assert.True(t, Of().MapWhile(double).IsEmpty())
+	assert.False(t, called)
+
+	toz := func(val interface{}) (interface{}, bool) { return 0, true }
+	assert.Equal(t, Of(0), Of(1).MapWhile(toz))
+	assert.True(t, Of(1).MapWhile(toz, ZeroValueIsEmpty).IsEmpty())
+}
+
+func TestOptionalMapErr(t *testing.T) {
+	var (
+		called  = false
+		failure = fmt.Errorf("failure")
+		inc     = func(val interface{}) (interface{}, error) {
+			called = true
+			return val.(int) + 1, nil
+		}
+		fail = func(val interface{}) (interface{}, error) { return nil, failure }
+		nilf = func(val interface{}) (interface{}, error) { return nil, nil }
+		zero = func(val interface{}) (interface{}, error) { return 0, nil }
+	)
+
+	opt, err := Of().MapErr(inc)
+	assert.True(t, opt.IsEmpty())
+	assert.Nil(t, err)
+	assert.False(t, called)
+
+	opt, err = Of(1).MapErr(inc)
+	assert.Equal(t, Of(2), opt)
+	assert.Nil(t, err)
+
+	opt, err = Of(1).MapErr(fail)
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, failure, err)
+
+	opt, err = Of(1).MapErr(nilf)
+	assert.True(t, opt.IsEmpty())
+	assert.Nil(t, err)
+
+	opt, err = Of(1).MapErr(zero)
+	assert.Equal(t, Of(0), opt)
+	assert.Nil(t, err)
+
+	opt, err = Of(1).MapErr(zero, ZeroValueIsEmpty)
+	assert.True(t, opt.IsEmpty())
+	assert.Nil(t, err)
+}
+
+func TestOptionalMapAs(t *testing.T) {
+	var (
+		stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+		toStringer   = func(val int) OptionalT { return OptionalT(val) }
+		toInt        = func(val int) int { return val }
+	)
+
+	opt, err := Of(1).MapAs(toStringer, stringerType)
+	assert.Equal(t, Of(OptionalT(1)), opt)
+	assert.Nil(t, err)
+
+	opt, err = Of(1).MapAs(toInt, reflect.TypeOf(0))
+	assert.Equal(t, Of(1), opt)
+	assert.Nil(t, err)
+
+	opt, err = Of(1).MapAs(toInt, stringerType)
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, fmt.Errorf("the mapped value of type int is not assignable to fmt.Stringer"), err)
+
+	opt, err = Of().MapAs(toInt, stringerType)
+	assert.True(t, opt.IsEmpty())
+	assert.Nil(t, err)
+
+	opt, err = Of(0).MapAs(toInt, stringerType, ZeroValueIsEmpty)
+	assert.True(t, opt.IsEmpty())
+	assert.Nil(t, err)
+}
+
+func TestOptionalMapAssert(t *testing.T) {
+	var (
+		intType     = reflect.TypeOf(0)
+		stringer    = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+		called      = false
+		double      = func(v interface{}) interface{} { called = true; return v.(int) * 2 }
+		stringValue = func(v interface{}) interface{} { return v.(fmt.Stringer).String() }
+	)
+
+	assert.Equal(t, Of(4), Of(2).MapAssert(intType, double))
+	assert.True(t, called)
+
+	called = false
+	assert.Equal(t, Of(), Of("2").MapAssert(intType, double))
+	assert.Equal(t, Of(), Of().MapAssert(intType, double))
+	assert.False(t, called)
+
+	assert.Equal(t, Of(), Of(0).MapAssert(intType, double, ZeroValueIsEmpty))
+	assert.Equal(t, Of("2"), Of(OptionalT(1)).MapAssert(stringer, stringValue))
+}
+
+func TestOptionalMapIfType(t *testing.T) {
+	var (
+		called   = false
+		double   = func(i int) int { called = true; return i * 2 }
+		upper    = func(s string) string { return strings.ToUpper(s) }
+		stringer = func(s fmt.Stringer) string { return s.String() }
+	)
+
+	assert.Equal(t, Of(4), Of(2).MapIfType(double))
+	assert.Equal(t, Of(4), Of(2).MapIfType(upper).MapIfType(double))
+	assert.Equal(t, Of("A"), Of("a").MapIfType(double).MapIfType(upper))
+	assert.Equal(t, Of("2"), Of(OptionalT(1)).MapIfType(stringer))
+	assert.Equal(t, Of(), Of(0).MapIfType(double, ZeroValueIsEmpty))
+
+	called = false
+	assert.Equal(t, Of(2.5), Of(2.5).MapIfType(double))
+	assert.Equal(t, Of(), Of().MapIfType(double))
+	assert.False(t, called)
+
+	func() {
+		defer func() {
+			assert.Equal(t, "MapIfType requires a func of one parameter that returns one value, not func()", recover())
+		}()
+
+		Of(1).MapIfType(func() {})
+		assert.Fail(t, "Expected Panic")
+	}()
+}
+
+func TestOptionalMapMust(t *testing.T) {
+	inc := func(val int) int { return val + 1 }
+	assert.Equal(t, 2, Of(1).MapMust(inc).MustGet())
+
+	toz := func(val interface{}) interface{} { return 0 }
+	assert.Equal(t, 0, Of(1).MapMust(toz).MustGet())
+	assert.True(t, Of(1).MapMust(toz, ZeroValueIsEmpty).IsEmpty())
+
+	func() {
+		defer func() {
+			assert.True(t, errNotPresent == recover())
+		}()
+
+		Of().MapMust(inc)
+		assert.Fail(t, "Expected Panic")
+	}()
+}
+
+func TestOptionalMapAll(t *testing.T) {
+	var (
+		opts   = []Optional{Of(1), Of(), Of(0), Of(3)}
+		inc    = func(val int) int { return val + 1 }
+		result = MapAll(opts, inc)
+	)
+	assert.Equal(t, []Optional{Of(2), Of(), Of(1), Of(4)}, result)
+	assert.Equal(t, []Optional{Of(1), Of(), Of(0), Of(3)}, opts)
+
+	dec := func(val int) int { return val - 1 }
+	assert.Equal(t, []Optional{Of(), Of(), Of(-1), Of(2)}, MapAll(opts, dec, ZeroValueIsEmpty))
+
+	assert.Equal(t, []Optional{}, MapAll(nil, inc))
+}
+
+func TestOptionalCollect(t *testing.T) {
+	type record struct {
+		Name  string
+		Age   int
+		Notes interface{}
+	}
+
+	rec := record{"a", 1, "notes"}
+	assert.Nil(t, Collect(&rec, Of("b"), Of(), Of(2)))
+	assert.Equal(t, record{"b", 1, 2}, rec)
+
+	assert.Nil(t, Collect(&rec, Of(), Of(), Of()))
+	assert.Equal(t, record{"b", 1, 2}, rec)
+
+	assert.Equal(t, fmt.Errorf("target must be a non-nil pointer to a struct, not gooptional.record"), Collect(rec))
+	assert.Equal(t, fmt.Errorf("target must be a non-nil pointer to a struct, not *int"), Collect(new(int)))
+	assert.Equal(t, fmt.Errorf("target must be a non-nil pointer to a struct, not *gooptional.record"), Collect((*record)(nil)))
+	assert.Equal(t, fmt.Errorf("gooptional.record has 3 fields, but 2 Optionals were provided"), Collect(&rec, Of(), Of()))
+
+	// Type mismatch leaves the target unmodified
+	assert.Equal(t,
+		fmt.Errorf("a value of type string cannot be assigned to field Age of type int"),
+		Collect(&rec, Of("c"), Of("d"), Of()),
+	)
+	assert.Equal(t, record{"b", 1, 2}, rec)
+
+	type private struct {
+		name string
+	}
+	assert.Equal(t, fmt.Errorf("field name of gooptional.private cannot be set"), Collect(&private{}, Of("a")))
+	assert.Nil(t, Collect(&private{}, Of()))
+}
+
+func TestOptionalPopulateOptionals(t *testing.T) {
+	type record struct {
+		Name    Optional
+		Age     Optional `optional:"age"`
+		Balance OptionalDecimal
+		ID      OptionalUUID
+		Skipped Optional `optional:"-"`
+		Plain   string
+		private Optional
+	}
+
+	rec := record{Age: Of(1), Skipped: Of(2), Plain: "plain"}
+	assert.Nil(t, PopulateOptionals(&rec, map[string]interface{}{
+		"Name":    "a",
+		"Balance": "1.5",
+		"ID":      optionalUUIDString,
+		"Skipped": 3,
+		"Plain":   "other",
+		"private": 4,
+	}))
+	assert.Equal(t, Of("a"), rec.Name)
+	assert.Equal(t, Of(), rec.Age)
+	assert.Equal(t, "Optional (1.5)", rec.Balance.String())
+	assert.Equal(t, OfUUID(optionalUUIDBytes), rec.ID)
+	assert.Equal(t, Of(2), rec.Skipped)
+	assert.Equal(t, "plain", rec.Plain)
+	assert.Equal(t, Of(), rec.private)
+
+	assert.Nil(t, PopulateOptionals(&rec, map[string]interface{}{"age": 5, "Name": nil}))
+	assert.Equal(t, Of(), rec.Name)
+	assert.Equal(t, Of(5), rec.Age)
+	assert.True(t, rec.Balance.IsEmpty())
+
+	assert.Equal(t, fmt.Errorf("target must be a non-nil pointer to a struct, not gooptional.record"), PopulateOptionals(rec, nil))
+	assert.Equal(t, fmt.Errorf("target must be a non-nil pointer to a struct, not *gooptional.record"), PopulateOptionals((*record)(nil), nil))
+
+	// Unscannable value leaves the target unmodified
+	assert.Equal(t,
+		fmt.Errorf("field Balance of gooptional.record cannot be populated from key Balance: %w", fmt.Errorf("cannot scan a value of type bool")),
+		PopulateOptionals(&rec, map[string]interface{}{"Name": "b", "Balance": true}),
+	)
+	assert.Equal(t, Of(), rec.Name)
+	assert.Equal(t, Of(5), rec.Age)
+
+	// Numbers decoded from JSON are float64
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"Name": "c", "age": 7, "Balance": 12.34, "ID": null}`), &m))
+	assert.Nil(t, PopulateOptionals(&rec, m))
+	assert.Equal(t, Of("c"), rec.Name)
+	assert.Equal(t, Of(7.0), rec.Age)
+	assert.Equal(t, "Optional (12.34)", rec.Balance.String())
+	assert.True(t, rec.ID.IsEmpty())
+
+	// Registered scan converters apply, and their errors are wrapped
+	type code string
+	codeType := reflect.TypeOf(code(""))
+	RegisterScanConverter(codeType, func(src interface{}) (interface{}, error) {
+		return strconv.Atoi(string(src.(code)))
+	})
+	defer RegisterScanConverter(codeType, nil)
+
+	assert.Nil(t, PopulateOptionals(&rec, map[string]interface{}{"Name": code("12")}))
+	assert.Equal(t, Of(12), rec.Name)
+
+	err := PopulateOptionals(&rec, map[string]interface{}{"Name": code("x")})
+	assert.Equal(t, `field Name of gooptional.record cannot be populated from key Name: strconv.Atoi: parsing "x": invalid syntax`, err.Error())
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr))
+	assert.Equal(t, "x", numErr.Num)
+	assert.Equal(t, Of(12), rec.Name)
+}
+
+func TestOptionalReduce(t *testing.T) {
+	sum := func(acc, val interface{}) interface{} { return acc.(int) + val.(int) }
+
+	assert.Equal(t, 10, Reduce(nil, 10, sum))
+	assert.Equal(t, 10, Reduce([]Optional{Of(), Of()}, 10, sum))
+	assert.Equal(t, 13, Reduce([]Optional{Of(1), Of(), Of(2)}, 10, sum))
+	assert.Equal(t, 16, Reduce([]Optional{Of(1), Of(2), Of(3)}, 10, sum))
+
+	concat := func(acc, val interface{}) interface{} { return acc.(string) + val.(string) }
+	assert.Equal(t, "ac", Reduce([]Optional{Of("a"), Of(), Of("c")}, "", concat))
+}
+
+func TestOptionalPartition(t *testing.T) {
+	present, emptyCount := Partition([]Optional{Of(), Of()})
+	assert.Nil(t, present)
+	assert.Equal(t, 2, emptyCount)
+
+	present, emptyCount = Partition([]Optional{Of(1), Of(), Of(""), Of()})
+	assert.Equal(t, []interface{}{1, ""}, present)
+	assert.Equal(t, 2, emptyCount)
+
+	present, emptyCount = Partition([]Optional{Of(1), Of(2)})
+	assert.Equal(t, []interface{}{1, 2}, present)
+	assert.Equal(t, 0, emptyCount)
+}
+
+func TestOptionalEqualValues(t *testing.T) {
+	opts := []Optional{Of("a"), Of(), Of("")}
+	assert.True(t, EqualValues(opts, "a", nil, ""))
+	assert.True(t, EqualValues(nil))
+
+	// length mismatch
+	assert.False(t, EqualValues(opts, "a", nil))
+	assert.False(t, EqualValues(opts, "a", nil, "", nil))
+
+	// null vs value mismatch
+	assert.False(t, EqualValues(opts, "a", "", ""))
+	assert.False(t, EqualValues(opts, nil, nil, ""))
 
-	tonp := func(val interface{}) interface{} {
-		return nil
-	}
-	assert.True(t, Of(1).Map(tonp).IsEmpty())
+	// value mismatch
+	assert.False(t, EqualValues(opts, "a", nil, "b"))
+}
 
-	toz := func(val interface{}) interface{} {
-		return 0
-	}
-	assert.False(t, Of(1).Map(toz).IsEmpty())
-	assert.True(t, Of(1).Map(toz, ZeroValueIsEmpty).IsEmpty())
+func TestOptionalCountByValue(t *testing.T) {
+	counts, emptyCount := CountByValue([]Optional{Of("a"), Of("b"), Of(), Of("a"), Of(1), Of(int64(1))})
+	assert.Equal(t, map[interface{}]int{"a": 2, "b": 1, 1: 1, int64(1): 1}, counts)
+	assert.Equal(t, 1, emptyCount)
+
+	counts, emptyCount = CountByValue([]Optional{Of(), Of()})
+	assert.Equal(t, map[interface{}]int{}, counts)
+	assert.Equal(t, 2, emptyCount)
+
+	counts, emptyCount = CountByValue(nil)
+	assert.Equal(t, map[interface{}]int{}, counts)
+	assert.Equal(t, 0, emptyCount)
+
+	// Values that are not comparable are keyed by their Go syntax
+	counts, emptyCount = CountByValue([]Optional{Of([]byte("x")), Of([]int{1}), Of([]byte("x")), Of(map[string]int{"a": 1}), Of()})
+	assert.Equal(t, map[interface{}]int{"[]byte{0x78}": 2, "[]int{1}": 1, `map[string]int{"a":1}`: 1}, counts)
+	assert.Equal(t, 1, emptyCount)
 }
 
 func TestOptionalFlatMap(t *testing.T) {
@@ -176,6 +1074,93 @@ func TestOptionalFlatMap(t *testing.T) {
 	assert.True(t, Of(1).FlatMap(toz).IsEmpty())
 }
 
+func TestOptionalFlatMapErr(t *testing.T) {
+	inc := func(val int) Optional { return Of(val + 1) }
+
+	opt, err := Of(1).FlatMapErr(inc)
+	assert.Equal(t, Of(2), opt)
+	assert.Nil(t, err)
+
+	opt, err = Of().FlatMapErr(inc)
+	assert.True(t, opt.IsEmpty())
+	assert.Nil(t, err)
+
+	// wrong return type
+	for _, f := range []interface{}{
+		nil,
+		1,
+		func(val int) int { return val },
+		func(val int) (Optional, error) { return Of(val), nil },
+		func(int, int) Optional { return Of() },
+	} {
+		opt, err = Of(1).FlatMapErr(f)
+		assert.True(t, opt.IsEmpty())
+		assert.Equal(t, fmt.Errorf("a func of one arg that returns an Optional is required, not %v", reflect.TypeOf(f)), err)
+
+		opt, err = Of().FlatMapErr(f)
+		assert.True(t, opt.IsEmpty())
+		assert.NotNil(t, err)
+	}
+
+	// wrong arg type
+	toSlice := func(val []int) Optional { return Of(val) }
+	opt, err = Of(1).FlatMapErr(toSlice)
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, fmt.Errorf("a value of type int cannot be passed to a func([]int) gooptional.Optional"), err)
+
+	// An integer is not converted to a string
+	toString := func(val string) Optional { return Of(val) }
+	opt, err = Of(65).FlatMapErr(toString)
+	assert.True(t, opt.IsEmpty())
+	assert.Equal(t, fmt.Errorf("a value of type int cannot be passed to a func(string) gooptional.Optional"), err)
+
+	// Other conversions are allowed
+	opt, err = Of(int8(1)).FlatMapErr(inc)
+	assert.Equal(t, Of(2), opt)
+	assert.Nil(t, err)
+}
+
+func TestOptionalGetOr(t *testing.T) {
+	missing := fmt.Errorf("email is required")
+
+	val, err := Of("a").GetOr(missing)
+	assert.Equal(t, "a", val)
+	assert.Nil(t, err)
+
+	val, err = Of().GetOr(missing)
+	assert.Nil(t, val)
+	assert.Equal(t, missing, err)
+}
+
+func TestOptionalMustGetNamed(t *testing.T) {
+	assert.Equal(t, "a", Of("a").MustGetNamed("email"))
+
+	func() {
+		defer func() {
+			assert.Equal(t, `field "email" not present`, recover())
+		}()
+
+		Of().MustGetNamed("email")
+		assert.Fail(t, "Expected Panic")
+	}()
+}
+
+func TestOptionalMustSatisfy(t *testing.T) {
+	positive := func(i int) bool { return i > 0 }
+	assert.Equal(t, 1, Of(1).MustSatisfy(positive, "must be positive"))
+
+	for _, opt := range []Optional{Of(0), Of()} {
+		func() {
+			defer func() {
+				assert.Equal(t, "must be positive", recover())
+			}()
+
+			opt.MustSatisfy(positive, "must be positive")
+			assert.Fail(t, "Expected Panic")
+		}()
+	}
+}
+
 func TestOptionalOrElseGetPanic(t *testing.T) {
 	f := func() interface{} { return 2 }
 	assert.Equal(t, 1, Of().OrElse(1))
@@ -199,6 +1184,113 @@ func TestOptionalOrElseGetPanic(t *testing.T) {
 	assert.Equal(t, 3, Of(3).OrElsePanic(errf))
 }
 
+func TestOptionalMergeInto(t *testing.T) {
+	assert.Equal(t, "update", Of("update").MergeInto("base"))
+	assert.Equal(t, "base", Of().MergeInto("base"))
+
+	assert.Equal(t, Of("update"), Of("update").MergeIntoOpt(Of("base")))
+	assert.Equal(t, Of("update"), Of("update").MergeIntoOpt(Of()))
+	assert.Equal(t, Of("base"), Of().MergeIntoOpt(Of("base")))
+	assert.Equal(t, Of(), Of().MergeIntoOpt(Of()))
+}
+
+func TestOptionalOrElseFrom(t *testing.T) {
+	var (
+		called = false
+		conv   = func(s string) int { called = true; return len(s) }
+	)
+
+	assert.Equal(t, 1, Of(1).OrElseFrom(Of("abc"), conv))
+	assert.False(t, called)
+
+	assert.Equal(t, 3, Of().OrElseFrom(Of("abc"), conv))
+	assert.True(t, called)
+
+	called = false
+	assert.Nil(t, Of().OrElseFrom(Of(), conv))
+	assert.False(t, called)
+}
+
+func TestOptionalOrElseOpt(t *testing.T) {
+	assert.Equal(t, 1, Of(1).OrElseOpt(Of(2)))
+	assert.Equal(t, 1, Of(1).OrElseOpt(Of()))
+	assert.Equal(t, 2, Of().OrElseOpt(Of(2)))
+	assert.Nil(t, Of().OrElseOpt(Of()))
+}
+
+func TestOptionalOrElseUpdate(t *testing.T) {
+	called := false
+	supplier := func() int {
+		called = true
+		return 2
+	}
+
+	opt := Of(1)
+	assert.True(t, opt == opt.OrElseUpdate(supplier))
+	assert.False(t, called)
+
+	assert.Equal(t, Of(2), Of().OrElseUpdate(supplier))
+	assert.True(t, called)
+
+	assert.Equal(t, Of(), Of().OrElseUpdate(func() interface{} { return nil }))
+}
+
+func TestOptionalOrElseConvert(t *testing.T) {
+	int64Type := reflect.TypeOf(int64(0))
+
+	val, err := Of(1).OrElseConvert(int64(2), int64Type)
+	assert.Equal(t, int64(1), val)
+	assert.Nil(t, err)
+
+	val, err = Of().OrElseConvert(int64(2), int64Type)
+	assert.Equal(t, int64(2), val)
+	assert.Nil(t, err)
+
+	val, err = Of(1.5).OrElseConvert(0, reflect.TypeOf(0))
+	assert.Equal(t, 1, val)
+	assert.Nil(t, err)
+
+	val, err = Of("a").OrElseConvert(0, int64Type)
+	assert.Nil(t, val)
+	assert.Equal(t, fmt.Errorf("cannot convert a value of type string to int64"), err)
+
+	val, err = Of(65).OrElseConvert("", reflect.TypeOf(""))
+	assert.Nil(t, val)
+	assert.Equal(t, fmt.Errorf("cannot convert a value of type int to string"), err)
+
+	val, err = Of().OrElseConvert(nil, int64Type)
+	assert.Nil(t, val)
+	assert.Equal(t, fmt.Errorf("cannot convert <nil> to int64"), err)
+
+	// Convertible types, but the value is not
+	arrayPtrType := reflect.TypeOf(&[4]int{})
+	if reflect.TypeOf([]int{}).ConvertibleTo(arrayPtrType) {
+		val, err = Of([]int{1, 2}).OrElseConvert(nil, arrayPtrType)
+		assert.Nil(t, val)
+		assert.True(t, strings.HasPrefix(err.Error(), "cannot convert a value of type []int to *[4]int: "))
+
+		val, err = Of([]int{1, 2, 3, 4}).OrElseConvert(nil, arrayPtrType)
+		assert.Equal(t, &[4]int{1, 2, 3, 4}, val)
+		assert.Nil(t, err)
+	}
+}
+
+func TestOptionalOrElseAs(t *testing.T) {
+	assert.Equal(t, 1.0, Of(1).OrElseAs(2.5))
+	assert.Equal(t, 2.5, Of().OrElseAs(2.5))
+	assert.Equal(t, 2.5, Of("a").OrElseAs(2.5))
+	assert.Equal(t, 1, Of(1).OrElseAs(nil))
+	assert.Nil(t, Of().OrElseAs(nil))
+
+	val, err := Of(1).OrElseAsErr(2.5)
+	assert.Equal(t, 1.0, val)
+	assert.Nil(t, err)
+
+	val, err = Of("a").OrElseAsErr(2.5)
+	assert.Nil(t, val)
+	assert.Equal(t, fmt.Errorf("cannot convert a value of type string to float64"), err)
+}
+
 func TestOptionalScan(t *testing.T) {
 	var opt Optional
 	assert.Nil(t, opt.Scan(0))
@@ -210,6 +1302,220 @@ func TestOptionalScan(t *testing.T) {
 	assert.NotNil(t, &sc)
 }
 
+func TestOptionalScanNull(t *testing.T) {
+	var (
+		opt  Optional
+		when = time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	)
+	for _, invalid := range []interface{}{
+		sql.NullBool{Bool: true},
+		sql.NullFloat64{Float64: 1},
+		sql.NullInt32{Int32: 1},
+		sql.NullInt64{Int64: 1},
+		sql.NullString{String: "a"},
+		sql.NullTime{Time: time.Now()},
+	} {
+		assert.Nil(t, opt.Scan(1))
+		assert.Nil(t, opt.Scan(invalid))
+		assert.True(t, opt.IsEmpty())
+		assert.Nil(t, opt.value)
+	}
+
+	for valid, val := range map[interface{}]interface{}{
+		sql.NullBool{Bool: false, Valid: true}:     false,
+		sql.NullFloat64{Float64: 1.5, Valid: true}: 1.5,
+		sql.NullInt32{Int32: 2, Valid: true}:       int32(2),
+		sql.NullInt64{Int64: 3, Valid: true}:       int64(3),
+		sql.NullString{String: "", Valid: true}:    "",
+		sql.NullTime{Time: when, Valid: true}:      when,
+	} {
+		assert.Nil(t, opt.Scan(valid))
+		assert.Equal(t, val, opt.MustGet())
+	}
+}
+
+func TestOptionalScanConverter(t *testing.T) {
+	stringType := reflect.TypeOf("")
+	RegisterScanConverter(stringType, func(src interface{}) (interface{}, error) {
+		return strconv.Atoi(src.(string))
+	})
+	defer RegisterScanConverter(stringType, nil)
+
+	var opt Optional
+	assert.Nil(t, opt.Scan("42"))
+	assert.Equal(t, 42, opt.MustGet())
+
+	assert.Nil(t, opt.Scan(sql.NullString{String: "43", Valid: true}))
+	assert.Equal(t, 43, opt.MustGet())
+
+	// Types without a converter are stored as is
+	assert.Nil(t, opt.Scan(int64(44)))
+	assert.Equal(t, int64(44), opt.MustGet())
+
+	// Nil is not converted
+	assert.Nil(t, opt.Scan(nil))
+	assert.True(t, opt.IsEmpty())
+
+	// A failed conversion leaves the Optional unmodified
+	assert.Nil(t, opt.Scan("45"))
+	assert.NotNil(t, opt.Scan("x"))
+	assert.Equal(t, 45, opt.MustGet())
+
+	// Removing the converter restores storing as is
+	RegisterScanConverter(stringType, nil)
+	assert.Nil(t, opt.Scan("46"))
+	assert.Equal(t, "46", opt.MustGet())
+}
+
+func TestOptionalScanText(t *testing.T) {
+	var opt Optional
+	assert.Nil(t, opt.ScanText("a", `\N`))
+	assert.Equal(t, "a", opt.MustGet())
+
+	assert.Nil(t, opt.ScanText(`\N`, `\N`))
+	assert.True(t, opt.IsEmpty())
+
+	assert.Nil(t, opt.ScanText("", `\N`))
+	assert.Equal(t, "", opt.MustGet())
+
+	assert.Nil(t, opt.ScanText("NULL", "NULL"))
+	assert.True(t, opt.IsEmpty())
+
+	assert.Nil(t, opt.ScanText("", ""))
+	assert.True(t, opt.IsEmpty())
+}
+
+func TestOptionalScanStrict(t *testing.T) {
+	var opt Optional
+	assert.Nil(t, opt.ScanStrict(1))
+	assert.Equal(t, 1, opt.MustGet())
+
+	assert.Equal(t, fmt.Errorf("cannot scan a value of kind func"), opt.ScanStrict(func() {}))
+	assert.Equal(t, fmt.Errorf("cannot scan a value of kind chan"), opt.ScanStrict(make(chan int)))
+	assert.Equal(t, fmt.Errorf("cannot scan a value of kind complex128"), opt.ScanStrict(1i))
+	assert.Equal(t, 1, opt.MustGet())
+
+	assert.Nil(t, opt.ScanStrict(nil))
+	assert.True(t, opt.IsEmpty())
+
+	// The default remains permissive
+	assert.Nil(t, opt.Scan(func() {}))
+	assert.True(t, opt.IsPresent())
+}
+
+func TestOptionalScanDelta(t *testing.T) {
+	var opt Optional
+	for _, step := range []struct {
+		src     interface{}
+		changed bool
+	}{
+		{nil, false},
+		{1, true},
+		{2, false},
+		{nil, true},
+		{nil, false},
+		{0, true},
+	} {
+		changed, err := opt.ScanDelta(step.src)
+		assert.Equal(t, step.changed, changed)
+		assert.Nil(t, err)
+		assert.Equal(t, step.src != nil, opt.IsPresent())
+	}
+}
+
+// optionalRows is a mock of a single column *sql.Rows
+type optionalRows struct {
+	values  []interface{}
+	index   int
+	scanErr error
+	err     error
+}
+
+func (r *optionalRows) Next() bool {
+	r.index++
+	return r.index <= len(r.values)
+}
+
+func (r *optionalRows) Scan(dest ...interface{}) error {
+	if r.scanErr != nil {
+		return r.scanErr
+	}
+
+	return dest[0].(sql.Scanner).Scan(r.values[r.index-1])
+}
+
+func (r *optionalRows) Err() error {
+	return r.err
+}
+
+func TestOptionalOnlyChangedScan(t *testing.T) {
+	var (
+		opt     Optional
+		first   = []byte("a")
+		same    = []byte("a")
+		differs = []byte("b")
+	)
+
+	changed, err := opt.OnlyChangedScan(nil)
+	assert.False(t, changed)
+	assert.Nil(t, err)
+
+	changed, err = opt.OnlyChangedScan(first)
+	assert.True(t, changed)
+	assert.Nil(t, err)
+
+	// Scanning an equal value keeps the original reference
+	changed, err = opt.OnlyChangedScan(same)
+	assert.False(t, changed)
+	assert.Nil(t, err)
+	assert.True(t, &first[0] == &opt.MustGet().([]byte)[0])
+
+	// Scanning a different value replaces it
+	changed, err = opt.OnlyChangedScan(differs)
+	assert.True(t, changed)
+	assert.Nil(t, err)
+	assert.True(t, &differs[0] == &opt.MustGet().([]byte)[0])
+
+	changed, err = opt.OnlyChangedScan(nil)
+	assert.True(t, changed)
+	assert.Nil(t, err)
+	assert.True(t, opt.IsEmpty())
+
+	// A failed conversion leaves the Optional unmodified
+	RegisterScanConverter(reflect.TypeOf(""), func(src interface{}) (interface{}, error) {
+		return strconv.Atoi(src.(string))
+	})
+	defer RegisterScanConverter(reflect.TypeOf(""), nil)
+
+	changed, err = opt.OnlyChangedScan("x")
+	assert.False(t, changed)
+	assert.NotNil(t, err)
+	assert.True(t, opt.IsEmpty())
+}
+
+func TestOptionalScanAll(t *testing.T) {
+	var rows Rows = &optionalRows{values: []interface{}{"a", nil, int64(0)}}
+	opts, err := ScanAll(rows)
+	assert.Equal(t, []Optional{Of("a"), Of(), Of(int64(0))}, opts)
+	assert.Nil(t, err)
+
+	opts, err = ScanAll(&optionalRows{})
+	assert.Nil(t, opts)
+	assert.Nil(t, err)
+
+	scanErr := fmt.Errorf("scan failed")
+	opts, err = ScanAll(&optionalRows{values: []interface{}{"a"}, scanErr: scanErr})
+	assert.Nil(t, opts)
+	assert.Equal(t, scanErr, err)
+
+	iterErr := fmt.Errorf("iteration failed")
+	opts, err = ScanAll(&optionalRows{values: []interface{}{"a"}, err: iterErr})
+	assert.Equal(t, []Optional{Of("a")}, opts)
+	assert.Equal(t, iterErr, err)
+
+	var _ Rows = (*sql.Rows)(nil)
+}
+
 func TestOptionalValue(t *testing.T) {
 	val, err := Of().Value()
 	assert.Nil(t, val)
@@ -218,6 +1524,144 @@ func TestOptionalValue(t *testing.T) {
 	val, err = Of(0).Value()
 	assert.Equal(t, 0, val)
 	assert.Nil(t, err)
+
+	val, err = Of(float32(1.5)).Value()
+	assert.Equal(t, 1.5, val)
+	assert.Nil(t, err)
+
+	val, err = Of(sql.NullString{String: "a", Valid: true}).Value()
+	assert.Equal(t, "a", val)
+	assert.Nil(t, err)
+
+	// Types that the default driver converter does not accept are left for the driver to decide
+	val, err = Of([]string{"a"}).Value()
+	assert.Equal(t, []string{"a"}, val)
+	assert.Nil(t, err)
+
+	val, err = Of(map[string]int{"a": 1}).Value()
+	assert.Equal(t, map[string]int{"a": 1}, val)
+	assert.Nil(t, err)
+
+	val, err = OfUUID(optionalUUIDBytes).AsOptional().Value()
+	assert.Equal(t, optionalUUIDBytes, val)
+	assert.Nil(t, err)
+
+	val, err = Of(OptionalS{"a", 1}).Value()
+	assert.Equal(t, OptionalS{"a", 1}, val)
+	assert.Nil(t, err)
+}
+
+func TestOptionalScanValueRoundTrip(t *testing.T) {
+	// Each driver value scans into an Optional whose Value is the same driver value
+	for _, src := range []interface{}{
+		int64(0),
+		int64(-12),
+		1.5,
+		true,
+		"",
+		"a",
+		[]byte("b"),
+		time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC),
+	} {
+		var opt Optional
+		assert.Nil(t, opt.Scan(src))
+
+		val, err := opt.Value()
+		assert.Equal(t, src, val)
+		assert.Nil(t, err)
+
+		var opt2 Optional
+		assert.Nil(t, opt2.Scan(val))
+		assert.Equal(t, opt, opt2)
+	}
+
+	// Sized numbers become the int64 or float64 a driver would provide
+	for src, driverVal := range map[interface{}]interface{}{
+		int8(2):    int64(2),
+		int32(3):   int64(3),
+		uint16(4):  int64(4),
+		float32(5): float64(5),
+	} {
+		val, err := Of(src).Value()
+		assert.Equal(t, driverVal, val)
+		assert.Nil(t, err)
+	}
+
+	// NULL
+	for _, src := range []interface{}{nil, sql.NullInt64{}, sql.NullString{}} {
+		var opt Optional
+		assert.Nil(t, opt.Scan(src))
+		assert.True(t, opt.IsEmpty())
+
+		val, err := opt.Value()
+		assert.Nil(t, val)
+		assert.Nil(t, err)
+	}
+
+	// OptionalDecimal
+	dec, _ := OfDecimalString("12.50")
+	val, err := dec.Value()
+	assert.Nil(t, err)
+
+	var dec2 OptionalDecimal
+	assert.Nil(t, dec2.Scan(val))
+	assert.True(t, dec.Equal(dec2))
+
+	val, err = OfDecimal().Value()
+	assert.Nil(t, val)
+	assert.Nil(t, err)
+	assert.Nil(t, dec2.Scan(val))
+	assert.True(t, dec2.IsEmpty())
+}
+
+type OptionalG struct {
+	Name string
+}
+
+// OptionalGUnregistered is never registered with gob
+type OptionalGUnregistered struct {
+	Name string
+}
+
+func TestOptionalGob(t *testing.T) {
+	roundTrip := func(opt Optional) Optional {
+		var buf bytes.Buffer
+		assert.Nil(t, gob.NewEncoder(&buf).Encode(opt))
+
+		var result Optional
+		assert.Nil(t, gob.NewDecoder(&buf).Decode(&result))
+		return result
+	}
+
+	// Scalars do not need registering
+	assert.Equal(t, Of(1), roundTrip(Of(1)))
+	assert.Equal(t, Of(""), roundTrip(Of("")))
+	assert.Equal(t, Of("a"), roundTrip(Of("a")))
+	assert.Equal(t, Of(1.5), roundTrip(Of(1.5)))
+	assert.Equal(t, Of([]byte("a")), roundTrip(Of([]byte("a"))))
+	assert.Equal(t, Of(), roundTrip(Of()))
+
+	// Decoding replaces a present value with an empty one
+	var buf bytes.Buffer
+	assert.Nil(t, gob.NewEncoder(&buf).Encode(Of()))
+	result := Of(1)
+	assert.Nil(t, gob.NewDecoder(&buf).Decode(&result))
+	assert.Equal(t, Of(), result)
+
+	// A nil value is empty, even if the data claims it is present
+	buf.Reset()
+	assert.Nil(t, gob.NewEncoder(&buf).Encode(gobOptional{Present: true}))
+	result = Of(1)
+	assert.Nil(t, result.GobDecode(buf.Bytes()))
+	assert.Equal(t, Of(), result)
+
+	// Structs need registering
+	_, err := Of(OptionalGUnregistered{"a"}).GobEncode()
+	assert.NotNil(t, err)
+	RegisterGob(OptionalG{})
+	assert.Equal(t, Of(OptionalG{"a"}), roundTrip(Of(OptionalG{"a"})))
+
+	assert.NotNil(t, result.GobDecode([]byte("bad")))
 }
 
 type OptionalT int
@@ -230,4 +1674,155 @@ func TestOptionalString(t *testing.T) {
 	assert.Equal(t, emptyString, fmt.Sprintf("%s", Of()))
 	assert.Equal(t, "Optional (1)", fmt.Sprintf("%s", Of(1)))
 	assert.Equal(t, "Optional (2)", fmt.Sprintf("%s", Of(OptionalT(1))))
+
+	// Format behaves as if Optional was not a fmt.Formatter
+	assert.Equal(t, "Optional (1)", fmt.Sprintf("%+v", Of(1)))
+	assert.Equal(t, `"Optional (1)"`, fmt.Sprintf("%q", Of(1)))
+	assert.Equal(t, "  Optional", fmt.Sprintf("%10s", Of()))
+	assert.Equal(t, "4f7074696f6e616c", fmt.Sprintf("%x", Of()))
+	assert.Equal(t, "{1 %!d(bool=true) %!d(bool=false)}", fmt.Sprintf("%d", Of(1)))
+	assert.Equal(t, "{%!t(int=1) true false}", fmt.Sprintf("%t", Of(1)))
+}
+
+func TestOptionalRedacted(t *testing.T) {
+	opt := Of("secret").Redacted()
+	assert.True(t, opt.IsRedacted())
+	assert.False(t, Of("secret").IsRedacted())
+	assert.Equal(t, "Optional (****)", opt.String())
+	assert.Equal(t, "Optional (****)", fmt.Sprintf("%v", opt))
+	assert.Equal(t, "Optional (****)", fmt.Sprintf("%#v", opt))
+	for _, verb := range []string{"%s", "%+v", "%d", "%t", "%x", "%X", "%q", "%5.2f", "%-20s"} {
+		assert.Equal(t, "Optional (****)", fmt.Sprintf(verb, opt), verb)
+	}
+	assert.Equal(t, "[Optional (****)]", fmt.Sprintf("%d", []Optional{Of(1).Redacted()}))
+	assert.Equal(t, "secret", opt.MustGet())
+	assert.Equal(t, "secret", opt.ValueString())
+	assert.Equal(t, "'****'", opt.SQLLiteral())
+	assert.Equal(t, "NULL", Of().Redacted().SQLLiteral())
+
+	// Filter preserves redaction, Map does not
+	assert.Equal(t, "Optional (****)", opt.Filter(func(string) bool { return true }).String())
+	assert.Equal(t, "Optional (6)", opt.Map(func(s string) int { return len(s) }).String())
+
+	assert.Equal(t, emptyString, Of().Redacted().String())
+	assert.Equal(t, emptyString, Of().Redacted().GoString())
+
+	// Redaction is sticky on the receiver of decoding methods
+	opt = Of("secret").Redacted()
+	assert.Nil(t, opt.Scan("other"))
+	assert.True(t, opt.IsRedacted())
+	assert.Equal(t, "other", opt.MustGet())
+	assert.Nil(t, opt.Scan(nil))
+	assert.True(t, opt.IsRedacted())
+	assert.True(t, opt.IsEmpty())
+
+	opt = Of("secret").Redacted()
+	changed, err := opt.OnlyChangedScan("other")
+	assert.True(t, changed)
+	assert.Nil(t, err)
+	assert.True(t, opt.IsRedacted())
+	assert.Equal(t, "other", opt.MustGet())
+	changed, err = opt.OnlyChangedScan(nil)
+	assert.True(t, changed)
+	assert.Nil(t, err)
+	assert.True(t, opt.IsRedacted())
+	assert.True(t, opt.IsEmpty())
+
+	data, err := Of("other").GobEncode()
+	assert.Nil(t, err)
+	opt = Of("secret").Redacted()
+	assert.Nil(t, opt.GobDecode(data))
+	assert.True(t, opt.IsRedacted())
+	assert.Equal(t, "other", opt.MustGet())
+
+	// An unredacted receiver stays unredacted
+	plain := Of("secret")
+	assert.Nil(t, plain.Scan("other"))
+	assert.False(t, plain.IsRedacted())
+}
+
+func TestOptionalSetValueFormatter(t *testing.T) {
+	SetValueFormatter(func(value interface{}) string { return strings.ToUpper(fmt.Sprintf("%v", value)) })
+	defer SetValueFormatter(nil)
+
+	assert.Equal(t, emptyString, Of().String())
+	assert.Equal(t, "Optional (ABC)", Of("abc").String())
+	assert.Equal(t, "Optional (ABC)", fmt.Sprintf("%s", Of("abc")))
+	assert.Equal(t, "abc", Of("abc").ValueString())
+	assert.Equal(t, "Optional (1/3)", OfDecimal(big.NewRat(1, 3)).String())
+
+	SetValueFormatter(func(interface{}) string { return "****" })
+	assert.Equal(t, "Optional (****)", Of("secret").String())
+	assert.Equal(t, "Optional (****)", OfDecimal(big.NewRat(3, 2)).String())
+
+	SetValueFormatter(nil)
+	assert.Equal(t, "Optional (abc)", Of("abc").String())
+}
+
+func TestOptionalValueString(t *testing.T) {
+	assert.Equal(t, "", Of().ValueString())
+	assert.Equal(t, "1", Of(1).ValueString())
+	assert.Equal(t, "", Of("").ValueString())
+	assert.Equal(t, "2", Of(OptionalT(1)).ValueString())
+	assert.Equal(t, "{a 1}", Of(OptionalS{"a", 1}).ValueString())
+}
+
+func TestOptionalSQLLiteral(t *testing.T) {
+	assert.Equal(t, "NULL", Of().SQLLiteral())
+	assert.Equal(t, "'it''s'", Of("it's").SQLLiteral())
+	assert.Equal(t, "''", Of("").SQLLiteral())
+	assert.Equal(t, "'b'", Of([]byte("b")).SQLLiteral())
+	assert.Equal(t, "42", Of(42).SQLLiteral())
+	assert.Equal(t, "-7", Of(int64(-7)).SQLLiteral())
+	assert.Equal(t, "8", Of(int8(8)).SQLLiteral())
+	assert.Equal(t, "18446744073709551615", Of(uint64(math.MaxUint64)).SQLLiteral())
+	assert.Equal(t, "9", Of(uint(9)).SQLLiteral())
+	assert.Equal(t, "1.5", Of(1.5).SQLLiteral())
+	assert.Equal(t, "TRUE", Of(true).SQLLiteral())
+	assert.Equal(t, "FALSE", Of(false).SQLLiteral())
+	assert.Equal(t, "'2020-05-01T12:00:00Z'", Of(time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)).SQLLiteral())
+	assert.Equal(t, "'{a''s 1}'", Of(OptionalS{"a's", 1}).SQLLiteral())
+
+	// A present value that converts to nil
+	assert.Equal(t, "NULL", Of(sql.NullString{}).SQLLiteral())
+	assert.Equal(t, "NULL", Of(sql.NullInt64{}).SQLLiteral())
+	assert.Equal(t, "'a'", Of(sql.NullString{String: "a", Valid: true}).SQLLiteral())
+
+	// Floats that are not finite
+	assert.Equal(t, "'NaN'", Of(math.NaN()).SQLLiteral())
+	assert.Equal(t, "'Infinity'", Of(math.Inf(1)).SQLLiteral())
+	assert.Equal(t, "'-Infinity'", Of(math.Inf(-1)).SQLLiteral())
+	assert.Equal(t, "'Infinity'", Of(float32(math.Inf(1))).SQLLiteral())
+}
+
+func TestOptionalAsString(t *testing.T) {
+	assert.Equal(t, Of(), Of().AsString())
+	assert.Equal(t, Of("1"), Of(1).AsString())
+	assert.Equal(t, Of(""), Of("").AsString())
+	assert.Equal(t, Of("2"), Of(OptionalT(1)).AsString())
+}
+
+func TestOptionalAddTo(t *testing.T) {
+	values := url.Values{}
+	Of("a").AddTo(values, "name")
+	Of().AddTo(values, "missing")
+	Of(1).AddTo(values, "page")
+	Of(OptionalT(1)).AddTo(values, "page")
+	Of("").AddTo(values, "blank")
+
+	assert.Equal(t, url.Values{"name": {"a"}, "page": {"1", "2"}, "blank": {""}}, values)
+	assert.Equal(t, "blank=&name=a&page=1&page=2", values.Encode())
+}
+
+type OptionalS struct {
+	Name string
+	Age  int
+}
+
+func TestOptionalGoString(t *testing.T) {
+	assert.Equal(t, emptyString, fmt.Sprintf("%#v", Of()))
+	assert.Equal(t, "Optional (1)", fmt.Sprintf("%#v", Of(1)))
+	assert.Equal(t, `Optional ("a")`, fmt.Sprintf("%#v", Of("a")))
+	assert.Equal(t, `Optional (gooptional.OptionalS{Name:"a", Age:1})`, fmt.Sprintf("%#v", Of(OptionalS{"a", 1})))
+	assert.Equal(t, "Optional ({a 1})", fmt.Sprintf("%v", Of(OptionalS{"a", 1})))
 }
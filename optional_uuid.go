@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+)
+
+// OptionalUUID is an immutable wrapper for a UUID, stored as a [16]byte.
+// The nil UUID of all zero bytes is a present value, distinct from an empty OptionalUUID.
+// The only mutable operations are the implementations of the sql.Scanner, encoding.TextUnmarshaler, and encoding.BinaryUnmarshaler interfaces.
+// The zero value is ready to use.
+type OptionalUUID struct {
+	value   [16]byte
+	present bool
+}
+
+const (
+	// uuidStringLen is the length of the canonical form of a UUID, eg 123e4567-e89b-12d3-a456-426614174000
+	uuidStringLen = 36
+)
+
+// uuidDashes are the indexes of the dashes in the canonical form of a UUID
+var uuidDashes = []int{8, 13, 18, 23}
+
+// OfUUID returns an OptionalUUID.
+// If no value is provided, a new empty OptionalUUID is returned.
+// Otherwise a new OptionalUUID that wraps the value is returned.
+func OfUUID(value ...[16]byte) OptionalUUID {
+	if len(value) == 0 {
+		return OptionalUUID{}
+	}
+
+	return OptionalUUID{value: value[0], present: true}
+}
+
+// OfUUIDString returns an OptionalUUID of the given string, which must be in the canonical form of 8-4-4-4-12 hex digits.
+// Hex digits may be upper or lower case.
+// An empty string results in an empty OptionalUUID.
+// An error is returned if the string cannot be parsed.
+func OfUUIDString(value string) (OptionalUUID, error) {
+	if value == "" {
+		return OptionalUUID{}, nil
+	}
+
+	var (
+		err = fmt.Errorf("cannot parse %q as a uuid", value)
+		hx  = make([]byte, 0, 32)
+	)
+
+	if len(value) != uuidStringLen {
+		return OptionalUUID{}, err
+	}
+
+	start := 0
+	for _, dash := range uuidDashes {
+		if value[dash] != '-' {
+			return OptionalUUID{}, err
+		}
+
+		hx = append(hx, value[start:dash]...)
+		start = dash + 1
+	}
+	hx = append(hx, value[start:]...)
+
+	var result [16]byte
+	if _, decodeErr := hex.Decode(result[:], hx); decodeErr != nil {
+		return OptionalUUID{}, err
+	}
+
+	return OptionalUUID{value: result, present: true}, nil
+}
+
+// Get returns the wrapped value and whether or not it is present.
+// The wrapped value is only valid if the boolean is true.
+func (o OptionalUUID) Get() ([16]byte, bool) {
+	return o.value, o.present
+}
+
+// MustGet returns the unwrapped value and panics if it is not present.
+func (o OptionalUUID) MustGet() [16]byte {
+	if !o.present {
+		panic(errNotPresent)
+	}
+
+	return o.value
+}
+
+// AsOptional returns a generic Optional wrapping the [16]byte value if present, else an empty Optional.
+func (o OptionalUUID) AsOptional() Optional {
+	if !o.present {
+		return Optional{}
+	}
+
+	return Optional{value: o.value, present: true}
+}
+
+// ToOptionalUUID converts a generic Optional into an OptionalUUID, returning true if the conversion succeeded.
+// The conversion succeeds if the Optional is empty, resulting in an empty OptionalUUID,
+// or if it is present and holds exactly a [16]byte, resulting in an OptionalUUID of the value.
+// If the Optional holds any other type, an empty OptionalUUID and false are returned.
+func ToOptionalUUID(opt Optional) (OptionalUUID, bool) {
+	if !opt.present {
+		return OptionalUUID{}, true
+	}
+
+	if u, isa := opt.value.([16]byte); isa {
+		return OfUUID(u), true
+	}
+
+	return OptionalUUID{}, false
+}
+
+// IsEmpty returns true if this OptionalUUID is not present
+func (o OptionalUUID) IsEmpty() bool {
+	return !o.present
+}
+
+// IsPresent returns true if this OptionalUUID is present
+func (o OptionalUUID) IsPresent() bool {
+	return o.present
+}
+
+// Equal returns true if both OptionalUUIDs are empty, or both are present with the same value.
+func (o OptionalUUID) Equal(opt OptionalUUID) bool {
+	return o == opt
+}
+
+// Fingerprint returns a stable FNV-1a hash of a presence byte followed by the 16 bytes of the UUID, for keying caches and bloom filters.
+// The presence byte gives the nil UUID of all zero bytes a different fingerprint than an empty OptionalUUID.
+// Since UUIDs are often chosen by clients, the fingerprint must not be relied on to be collision free.
+func (o OptionalUUID) Fingerprint() uint64 {
+	h := fnv.New64a()
+	if !o.present {
+		h.Write([]byte{0})
+		return h.Sum64()
+	}
+
+	h.Write([]byte{1})
+	h.Write(o.value[:])
+	return h.Sum64()
+}
+
+// uuidString returns the canonical lower case form of a UUID
+func uuidString(value [16]byte) string {
+	hx := hex.EncodeToString(value[:])
+	return hx[0:8] + "-" + hx[8:12] + "-" + hx[12:16] + "-" + hx[16:20] + "-" + hx[20:]
+}
+
+// MarshalText is the encoding.TextMarshaler interface.
+// If present, the canonical lower case form is returned, else an empty slice.
+func (o OptionalUUID) MarshalText() ([]byte, error) {
+	if !o.present {
+		return []byte{}, nil
+	}
+
+	return []byte(uuidString(o.value)), nil
+}
+
+// UnmarshalText is the encoding.TextUnmarshaler interface.
+// The text is parsed as by OfUUIDString, so empty text results in an empty OptionalUUID.
+// If the text cannot be parsed, an error is returned and the OptionalUUID is unmodified.
+func (o *OptionalUUID) UnmarshalText(text []byte) error {
+	opt, err := OfUUIDString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*o = opt
+	return nil
+}
+
+// MarshalBinary is the encoding.BinaryMarshaler interface, with a compact form for caching and IPC that is smaller than gob.
+// An empty OptionalUUID is a single 0 byte, and a present OptionalUUID is a 1 byte followed by the 16 bytes of the UUID.
+func (o OptionalUUID) MarshalBinary() ([]byte, error) {
+	if !o.present {
+		return []byte{0}, nil
+	}
+
+	return append([]byte{1}, o.value[:]...), nil
+}
+
+// UnmarshalBinary is the encoding.BinaryUnmarshaler interface, decoding the form produced by MarshalBinary.
+// If the data is not in that form, an error is returned and the OptionalUUID is unmodified.
+func (o *OptionalUUID) UnmarshalBinary(data []byte) error {
+	switch {
+	case (len(data) == 1) && (data[0] == 0):
+		*o = OptionalUUID{}
+		return nil
+	case (len(data) == 17) && (data[0] == 1):
+		copy(o.value[:], data[1:])
+		o.present = true
+		return nil
+	}
+
+	return fmt.Errorf("cannot unmarshal %d bytes as a binary uuid", len(data))
+}
+
+// Scan is database/sql Scanner interface, allowing users to read null uuid columns into an OptionalUUID.
+// A nil src results in an empty OptionalUUID.
+// A string src is parsed as by OfUUIDString.
+// A []byte src of 16 bytes is stored as is, which is how some databases return a binary uuid,
+// otherwise it is parsed as by OfUUIDString.
+// Any other type of src results in an error, and the OptionalUUID is unmodified.
+func (o *OptionalUUID) Scan(src interface{}) error {
+	var (
+		opt OptionalUUID
+		err error
+	)
+
+	switch v := src.(type) {
+	case nil:
+	case string:
+		opt, err = OfUUIDString(v)
+	case []byte:
+		if len(v) == 16 {
+			copy(opt.value[:], v)
+			opt.present = true
+		} else {
+			opt, err = OfUUIDString(string(v))
+		}
+	default:
+		return fmt.Errorf("cannot scan a value of type %T", src)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*o = opt
+	return nil
+}
+
+// ScanDelta is the same as Scan, and also returns true if the scan changed whether or not the OptionalUUID is present.
+// If Scan fails, the OptionalUUID is unmodified, so the result is false.
+func (o *OptionalUUID) ScanDelta(src interface{}) (bool, error) {
+	wasPresent := o.present
+	err := o.Scan(src)
+	return o.present != wasPresent, err
+}
+
+// Value is the database/sql/driver/Valuer interface, allowing users to write an OptionalUUID into a column.
+// If present, the value is written as the canonical lower case string, else nil is written.
+func (o OptionalUUID) Value() (driver.Value, error) {
+	if !o.present {
+		return nil, nil
+	}
+
+	return uuidString(o.value), nil
+}
+
+// String returns "Optional (uuid)" if present, else "Optional" if it is empty.
+// The uuid is the canonical lower case string, which is passed to any formatter set by SetValueFormatter.
+func (o OptionalUUID) String() string {
+	if !o.present {
+		return emptyString
+	}
+
+	return fmt.Sprintf("Optional (%s)", valueFormatter.Load().(func(interface{}) string)(uuidString(o.value)))
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gooptional
+
+import (
+	"database/sql"
+	"encoding"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var optionalUUIDBytes = [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+const optionalUUIDString = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestOptionalUUIDOfGet(t *testing.T) {
+	for _, opt := range []OptionalUUID{OfUUID(), {}} {
+		assert.True(t, opt.IsEmpty())
+		assert.False(t, opt.IsPresent())
+		_, valid := opt.Get()
+		assert.False(t, valid)
+
+		func() {
+			defer func() {
+				assert.True(t, errNotPresent == recover())
+			}()
+
+			opt.MustGet()
+			assert.Fail(t, "Expected Panic")
+		}()
+	}
+
+	opt := OfUUID(optionalUUIDBytes)
+	val, valid := opt.Get()
+	assert.Equal(t, optionalUUIDBytes, val)
+	assert.True(t, valid)
+	assert.Equal(t, optionalUUIDBytes, opt.MustGet())
+
+	// The nil uuid is present
+	assert.True(t, OfUUID([16]byte{}).IsPresent())
+	assert.False(t, OfUUID([16]byte{}).Equal(OfUUID()))
+}
+
+func TestOptionalUUIDZeroValueMatrix(t *testing.T) {
+	var (
+		zval OptionalUUID
+		of   = OfUUID()
+		uuid = OfUUID(optionalUUIDBytes)
+	)
+
+	for name, method := range map[string]func(OptionalUUID) interface{}{
+		"Get": func(o OptionalUUID) interface{} {
+			val, valid := o.Get()
+			return []interface{}{val, valid}
+		},
+		"MustGet": func(o OptionalUUID) interface{} {
+			defer func() { recover() }()
+			return o.MustGet()
+		},
+		"IsEmpty":     func(o OptionalUUID) interface{} { return o.IsEmpty() },
+		"IsPresent":   func(o OptionalUUID) interface{} { return o.IsPresent() },
+		"Equal":       func(o OptionalUUID) interface{} { return []bool{o.Equal(of), o.Equal(uuid)} },
+		"AsOptional":  func(o OptionalUUID) interface{} { return o.AsOptional() },
+		"Fingerprint": func(o OptionalUUID) interface{} { return o.Fingerprint() },
+		"MarshalText": func(o OptionalUUID) interface{} {
+			text, err := o.MarshalText()
+			return []interface{}{text, err}
+		},
+		"MarshalBinary": func(o OptionalUUID) interface{} {
+			data, err := o.MarshalBinary()
+			return []interface{}{data, err}
+		},
+		"Value": func(o OptionalUUID) interface{} {
+			val, err := o.Value()
+			return []interface{}{val, err}
+		},
+		"String": func(o OptionalUUID) interface{} { return o.String() },
+	} {
+		assert.Equal(t, method(of), method(zval), name)
+	}
+
+	assert.True(t, zval == of)
+}
+
+func TestOptionalUUIDAsOptional(t *testing.T) {
+	assert.Equal(t, Of(), OfUUID().AsOptional())
+	assert.Equal(t, Of(optionalUUIDBytes), OfUUID(optionalUUIDBytes).AsOptional())
+	assert.Equal(t, OfUUID(optionalUUIDBytes).AsOptional(), Of(OfUUID(optionalUUIDBytes)))
+
+	// matching type
+	opt, ok := ToOptionalUUID(Of(optionalUUIDBytes))
+	assert.True(t, ok)
+	assert.True(t, OfUUID(optionalUUIDBytes).Equal(opt))
+
+	// mismatched type
+	opt, ok = ToOptionalUUID(Of(optionalUUIDString))
+	assert.False(t, ok)
+	assert.True(t, opt.IsEmpty())
+
+	// empty
+	opt, ok = ToOptionalUUID(Of())
+	assert.True(t, ok)
+	assert.True(t, opt.IsEmpty())
+}
+
+func TestOptionalUUIDPointerMethods(t *testing.T) {
+	// Only decoding methods may modify an OptionalUUID
+	assert.Equal(t, []string{"Scan", "ScanDelta", "UnmarshalBinary", "UnmarshalText"}, pointerMethods(reflect.TypeOf(OptionalUUID{})))
+}
+
+func TestOptionalUUIDString(t *testing.T) {
+	opt, err := OfUUIDString(optionalUUIDString)
+	assert.Nil(t, err)
+	assert.True(t, OfUUID(optionalUUIDBytes).Equal(opt))
+
+	opt, err = OfUUIDString("123E4567-E89B-12D3-A456-426614174000")
+	assert.Nil(t, err)
+	assert.True(t, OfUUID(optionalUUIDBytes).Equal(opt))
+
+	opt, err = OfUUIDString("00000000-0000-0000-0000-000000000000")
+	assert.Nil(t, err)
+	assert.True(t, OfUUID([16]byte{}).Equal(opt))
+
+	opt, err = OfUUIDString("")
+	assert.Nil(t, err)
+	assert.True(t, opt.IsEmpty())
+
+	for _, str := range []string{
+		"123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400",
+		"123e4567-e89b-12d3-a456-4266141740000",
+		"123e4567-e89b-12d3-a456_426614174000",
+		"123e456-7e89b-12d3-a456-426614174000",
+		"123e4567-e89b-12d3-a456-42661417400g",
+		"{123e4567-e89b-12d3-a456-42661417400}",
+	} {
+		opt, err = OfUUIDString(str)
+		assert.Equal(t, fmt.Errorf("cannot parse %q as a uuid", str), err)
+		assert.True(t, opt.IsEmpty())
+	}
+
+	assert.Equal(t, emptyString, OfUUID().String())
+	assert.Equal(t, "Optional ("+optionalUUIDString+")", OfUUID(optionalUUIDBytes).String())
+}
+
+func TestOptionalUUIDText(t *testing.T) {
+	var (
+		_ encoding.TextMarshaler   = OptionalUUID{}
+		_ encoding.TextUnmarshaler = &OptionalUUID{}
+	)
+
+	text, err := OfUUID(optionalUUIDBytes).MarshalText()
+	assert.Equal(t, []byte(optionalUUIDString), text)
+	assert.Nil(t, err)
+
+	text, err = OfUUID().MarshalText()
+	assert.Equal(t, []byte{}, text)
+	assert.Nil(t, err)
+
+	var opt OptionalUUID
+	assert.Nil(t, opt.UnmarshalText([]byte(optionalUUIDString)))
+	assert.Equal(t, optionalUUIDBytes, opt.MustGet())
+
+	assert.Equal(t, fmt.Errorf("cannot parse %q as a uuid", "x"), opt.UnmarshalText([]byte("x")))
+	assert.Equal(t, optionalUUIDBytes, opt.MustGet())
+
+	assert.Nil(t, opt.UnmarshalText([]byte{}))
+	assert.True(t, opt.IsEmpty())
+}
+
+func TestOptionalUUIDBinary(t *testing.T) {
+	var (
+		_ encoding.BinaryMarshaler   = OptionalUUID{}
+		_ encoding.BinaryUnmarshaler = &OptionalUUID{}
+	)
+
+	data, err := OfUUID().MarshalBinary()
+	assert.Equal(t, []byte{0}, data)
+	assert.Nil(t, err)
+
+	data, err = OfUUID(optionalUUIDBytes).MarshalBinary()
+	assert.Equal(t, append([]byte{1}, optionalUUIDBytes[:]...), data)
+	assert.Nil(t, err)
+
+	// Round trips, including the nil uuid
+	for _, opt := range []OptionalUUID{OfUUID(), OfUUID([16]byte{}), OfUUID(optionalUUIDBytes)} {
+		data, err = opt.MarshalBinary()
+		assert.Nil(t, err)
+
+		result := OfUUID(optionalUUIDBytes)
+		assert.Nil(t, result.UnmarshalBinary(data))
+		assert.Equal(t, opt, result)
+	}
+
+	// Invalid data leaves the OptionalUUID unmodified
+	opt := OfUUID(optionalUUIDBytes)
+	for _, data := range [][]byte{nil, {}, {2}, {1}, {0, 0}, make([]byte, 17), append([]byte{1}, make([]byte, 17)...)} {
+		assert.Equal(t, fmt.Errorf("cannot unmarshal %d bytes as a binary uuid", len(data)), opt.UnmarshalBinary(data))
+		assert.Equal(t, OfUUID(optionalUUIDBytes), opt)
+	}
+}
+
+func TestOptionalUUIDScanValue(t *testing.T) {
+	var opt OptionalUUID
+	assert.Nil(t, opt.Scan(optionalUUIDString))
+	assert.Equal(t, optionalUUIDBytes, opt.MustGet())
+
+	assert.Nil(t, opt.Scan(nil))
+	assert.True(t, opt.IsEmpty())
+
+	assert.Nil(t, opt.Scan([]byte(optionalUUIDString)))
+	assert.Equal(t, optionalUUIDBytes, opt.MustGet())
+
+	assert.Nil(t, opt.Scan(optionalUUIDBytes[:]))
+	assert.Equal(t, optionalUUIDBytes, opt.MustGet())
+
+	assert.Equal(t, fmt.Errorf("cannot parse %q as a uuid", "x"), opt.Scan("x"))
+	assert.Equal(t, fmt.Errorf("cannot scan a value of type %T", 1), opt.Scan(1))
+	assert.Equal(t, optionalUUIDBytes, opt.MustGet())
+
+	sc := (sql.Scanner)(&opt)
+	assert.NotNil(t, &sc)
+
+	val, err := OfUUID().Value()
+	assert.Nil(t, val)
+	assert.Nil(t, err)
+
+	val, err = OfUUID(optionalUUIDBytes).Value()
+	assert.Equal(t, optionalUUIDString, val)
+	assert.Nil(t, err)
+
+	changed, err := opt.ScanDelta(nil)
+	assert.True(t, changed)
+	assert.Nil(t, err)
+
+	changed, err = opt.ScanDelta(nil)
+	assert.False(t, changed)
+	assert.Nil(t, err)
+}
+
+func TestOptionalUUIDFingerprint(t *testing.T) {
+	opt, _ := OfUUIDString(optionalUUIDString)
+	assert.Equal(t, OfUUID(optionalUUIDBytes).Fingerprint(), opt.Fingerprint())
+	assert.Equal(t, OfUUID().Fingerprint(), OptionalUUID{}.Fingerprint())
+	assert.False(t, uint64(0) == OfUUID().Fingerprint())
+	assert.False(t, OfUUID([16]byte{}).Fingerprint() == OfUUID().Fingerprint())
+	assert.False(t, OfUUID([16]byte{}).Fingerprint() == opt.Fingerprint())
+}